@@ -0,0 +1,67 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ChatCompletionsURL 智谱 chat completions 接口地址
+const ChatCompletionsURL = "https://open.bigmodel.cn/api/paas/v4/chat/completions"
+
+// ChatMessage chat completions 接口的消息，Content 可以是字符串或多模态内容数组
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+// ChatCompletion 以非流式方式调用 chat completions 接口，返回第一个候选的文本内容
+func ChatCompletion(ctx context.Context, apiKey, model string, messages []ChatMessage) (string, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"model":    model,
+		"messages": messages,
+		"stream":   false,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", ChatCompletionsURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API Error: %s", string(body))
+	}
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("API Error: empty choices")
+	}
+	return result.Choices[0].Message.Content, nil
+}
@@ -3,17 +3,22 @@ package client
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/toolcall"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/usage"
 	"github.com/gorilla/websocket"
 )
 
@@ -21,10 +26,19 @@ type RealtimeClient interface {
 	Connect() error
 	Disconnect() error
 	Send(event *events.Event) error
+	SendAudio(audio []byte) error
+	SendEvent(event events.ClientEvent) error
 	SendFrameByVideo(event *events.Event) error
 	FlushVideoFrames() error
 	Wait()
 	SetInstructions(instructions string)
+	SetUsageTracker(tracker *usage.Tracker)
+	SendText(ctx context.Context, text string) (*TextStream, error)
+	SetToolRegistry(registry *toolcall.Registry, autoResponse bool)
+	SetToolTimeout(timeout time.Duration)
+	SetValidateEvents(validate bool)
+	SetExtensions(registry *events.ExtensionRegistry)
+	SetProtocol(protocol Protocol)
 }
 
 type realtimeClient struct {
@@ -32,26 +46,56 @@ type realtimeClient struct {
 	onReceived  func(event *events.Event) error
 	conn        *websocket.Conn
 
-	isConnected bool
-	lock        sync.RWMutex
-	wg          *sync.WaitGroup
+	isConnected    bool
+	skipValidation bool
+	lock           sync.RWMutex
+	writeLock      sync.Mutex
+	wg             *sync.WaitGroup
 
 	videoFrames     [][]byte
 	videoFrameMutex sync.Mutex
 	maxFrameCount   int
 	instructions    string
+
+	// sessionTimeout 读取循环的最长运行时间，readTimeout 两条消息之间的最长间隔，为 0 表示不限制
+	sessionTimeout, readTimeout time.Duration
+
+	usage      *usage.Tracker
+	extensions *events.ExtensionRegistry
+	protocol   Protocol
+
+	// metadataEchoed 服务端是否会在 response.created 中返回 response.create 的 metadata，用于 SendText 匹配响应
+	metadataEchoed atomic.Bool
+
+	subscribers  map[uint64]func(event *events.Event)
+	subscriberID uint64
+	// readerDone 当前连接的读取循环已退出，之后注册的订阅者立即收到 nil
+	readerDone     bool
+	subscriberLock sync.Mutex
+
+	toolRegistry     *toolcall.Registry
+	toolDispatcher   *toolcall.Dispatcher
+	toolTimeout      time.Duration
+	unsubscribeTools func()
+	toolLock         sync.Mutex
 }
 
 const waitTimeout = 30 * time.Second // Define a default timeout for wait
 
+const readTimeout = 15 * time.Second
+
 func NewRealtimeClient(url, apiKey string, onReceived func(event *events.Event) error) *realtimeClient {
 	return &realtimeClient{
-		url:           url,
-		apiKey:        apiKey,
-		onReceived:    onReceived,
-		videoFrames:   make([][]byte, 0),
-		maxFrameCount: 10,
-		instructions:  "请描述这个视频的内容",
+		url:            url,
+		apiKey:         apiKey,
+		onReceived:     onReceived,
+		videoFrames:    make([][]byte, 0),
+		maxFrameCount:  10,
+		instructions:   "请描述这个视频的内容",
+		protocol:       glmProtocol{},
+		toolTimeout:    toolcall.DefaultTimeout,
+		sessionTimeout: waitTimeout,
+		readTimeout:    readTimeout,
 	}
 }
 
@@ -61,12 +105,7 @@ func (r *realtimeClient) Connect() error {
 	if r.isConnected {
 		return nil
 	}
-	var header http.Header
-	if r.apiKey != "" {
-		header = make(http.Header)
-		header.Set("Authorization", fmt.Sprintf("Bearer %s", r.apiKey))
-	}
-	c, rsp, err := websocket.DefaultDialer.Dial(r.url, header)
+	c, rsp, err := websocket.DefaultDialer.Dial(r.url, r.protocol.Header(r.apiKey))
 	if err != nil {
 		log.Printf("[RealtimeClient] WebSocket dial fail, url: %s, rsp: %v, err: %v\n", r.url, rsp, err)
 		return err
@@ -76,9 +115,12 @@ func (r *realtimeClient) Connect() error {
 		return nil
 	})
 	r.conn, r.isConnected, r.wg = c, true, &sync.WaitGroup{}
+	r.subscriberLock.Lock()
+	r.readerDone = false
+	r.subscriberLock.Unlock()
 
 	r.wg.Add(1)
-	go r.readWsMsg()
+	go r.readWsMsg(c)
 
 	return nil
 }
@@ -99,6 +141,27 @@ func (r *realtimeClient) Disconnect() (err error) {
 	return r.conn.Close()
 }
 
+// disconnectConn 读取循环退出时断开连接，连接已被替换时不影响新连接
+func (r *realtimeClient) disconnectConn(conn *websocket.Conn) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.isConnected || r.conn != conn {
+		return
+	}
+	r.isConnected = false
+	_ = conn.Close()
+}
+
+// waitReader 等待已断开连接的读取循环退出，避免其断开通知发给重新连接后的订阅者
+func (r *realtimeClient) waitReader() {
+	r.lock.RLock()
+	wg, connected := r.wg, r.isConnected
+	r.lock.RUnlock()
+	if wg != nil && !connected {
+		wg.Wait()
+	}
+}
+
 func (r *realtimeClient) Wait() {
 	log.Printf("[RealtimeClient] Waiting for exit with timeout %v ...\n", waitTimeout)
 
@@ -117,6 +180,49 @@ func (r *realtimeClient) Wait() {
 	}
 }
 
+// SetExtensions 设置自定义事件注册表，收到已注册类型的事件时解析为对应负载并调用处理函数，
+// 事件仍会以 Event 的形式交给 onReceived
+func (r *realtimeClient) SetExtensions(registry *events.ExtensionRegistry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.extensions = registry
+}
+
+// dispatchExtension 仅在事件类型已注册时才重新解析消息，音频增量等高频事件不会有额外开销
+func (r *realtimeClient) dispatchExtension(event *events.Event, message []byte) {
+	r.lock.RLock()
+	extensions := r.extensions
+	r.lock.RUnlock()
+	if extensions == nil || !extensions.Has(event.Type) {
+		return
+	}
+	if _, err := extensions.Dispatch(message); err != nil {
+		log.Printf("[RealtimeClient] Dispatch extension event failed, err: %v\n", err)
+	}
+}
+
+// SetValidateEvents 设置发送前是否校验客户端事件，默认开启，校验失败时 Send 返回 *events.ValidationError
+func (r *realtimeClient) SetValidateEvents(validate bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.skipValidation = !validate
+}
+
+// SendEvent 发送强类型的客户端事件，与 Send 经过相同的处理流程
+func (r *realtimeClient) SendEvent(event events.ClientEvent) error {
+	switch e := event.(type) {
+	case events.InputAudioBufferAppendEvent:
+		return r.sendAudio(e.EventID, e.Audio, e.ClientTimestamp)
+	case *events.InputAudioBufferAppendEvent:
+		return r.sendAudio(e.EventID, e.Audio, e.ClientTimestamp)
+	}
+	e, err := events.ToEvent(event)
+	if err != nil {
+		return fmt.Errorf("marshal %s event failed: %w", event.EventType(), err)
+	}
+	return r.Send(e)
+}
+
 func (r *realtimeClient) Send(event *events.Event) (err error) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -124,10 +230,110 @@ func (r *realtimeClient) Send(event *events.Event) (err error) {
 		log.Printf("[RealtimeClient] Sending event fail, err: not connected\n")
 		return fmt.Errorf("not connected")
 	}
+	if event.Type == events.RealtimeClientEventResponseCreate && r.usage != nil {
+		if exceeded := r.usage.Exceeded(); exceeded != nil {
+			log.Printf("[RealtimeClient] Refusing response.create, err: %v\n", exceeded)
+			return exceeded
+		}
+	}
+	r.withRegisteredTools(event)
+	if !r.skipValidation {
+		if err = event.Validate(); err != nil {
+			log.Printf("[RealtimeClient] Refusing invalid event, err: %v\n", err)
+			return err
+		}
+	}
+	r.cancelToolCalls(event)
 	if event.ClientTimestamp <= 0 {
 		event.ClientTimestamp = time.Now().UnixMilli()
 	}
-	if err = r.conn.WriteMessage(websocket.TextMessage, []byte(event.ToJson())); err != nil {
+	buf := encodeBuffers.Get().(*[]byte)
+	defer encodeBuffers.Put(buf)
+	message, err := r.encode(buf, event)
+	if err != nil {
+		log.Printf("[RealtimeClient] Encode %s event failed, err: %v\n", event.Type, err)
+		return err
+	}
+	return r.write(message)
+}
+
+// SendAudio 发送一段原始音频，音频直接 base64 编码到复用的缓冲区，无需调用方先编码为字符串
+func (r *realtimeClient) SendAudio(audio []byte) error {
+	return r.sendAudio("", audio, 0)
+}
+
+func (r *realtimeClient) sendAudio(eventID string, audio []byte, clientTimestamp int64) error {
+	r.lock.RLock()
+	_, fast := r.protocol.(glmProtocol)
+	r.lock.RUnlock()
+	if !fast {
+		return r.Send(&events.Event{
+			EventID:         eventID,
+			Type:            events.RealtimeClientEventInputAudioBufferAppend,
+			Audio:           base64.StdEncoding.EncodeToString(audio),
+			ClientTimestamp: clientTimestamp,
+		})
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if !r.isConnected {
+		log.Printf("[RealtimeClient] Sending event fail, err: not connected\n")
+		return fmt.Errorf("not connected")
+	}
+	if !r.skipValidation {
+		var fieldErr *events.FieldError
+		if len(audio) == 0 {
+			fieldErr = &events.FieldError{Field: "audio", Message: "is required"}
+		} else if len(audio) > events.MaxAudioAppendBytes {
+			fieldErr = &events.FieldError{Field: "audio", Message: fmt.Sprintf("must be at most %d bytes, got %d", events.MaxAudioAppendBytes, len(audio))}
+		}
+		if fieldErr != nil {
+			err := &events.ValidationError{EventType: events.RealtimeClientEventInputAudioBufferAppend, Errors: []events.FieldError{*fieldErr}}
+			log.Printf("[RealtimeClient] Refusing invalid event, err: %v\n", err)
+			return err
+		}
+	}
+	if clientTimestamp <= 0 {
+		clientTimestamp = time.Now().UnixMilli()
+	}
+	buf := encodeBuffers.Get().(*[]byte)
+	defer encodeBuffers.Put(buf)
+	message := events.AppendAudioEvent((*buf)[:0], eventID, audio, clientTimestamp)
+	keepBuffer(buf, message)
+	return r.write(message)
+}
+
+// encodeBuffers 发送事件时复用的编码缓冲区，音视频追加事件使用快速编码直接写入其中
+var encodeBuffers = sync.Pool{New: func() any {
+	buf := make([]byte, 0, 16<<10)
+	return &buf
+}}
+
+// maxPooledBuffer 超过该大小的缓冲区不放回池中，避免偶发的大视频帧长期占用内存
+const maxPooledBuffer = 1 << 20
+
+func keepBuffer(buf *[]byte, message []byte) {
+	if cap(message) <= maxPooledBuffer {
+		*buf = message[:0]
+	}
+}
+
+func (r *realtimeClient) encode(buf *[]byte, event *events.Event) ([]byte, error) {
+	if _, ok := r.protocol.(glmProtocol); ok {
+		if message, ok := events.AppendEvent((*buf)[:0], event); ok {
+			keepBuffer(buf, message)
+			return message, nil
+		}
+	}
+	return r.protocol.EncodeClientEvent(event)
+}
+
+func (r *realtimeClient) write(message []byte) error {
+	// websocket 连接不支持并发写
+	r.writeLock.Lock()
+	defer r.writeLock.Unlock()
+	err := r.conn.WriteMessage(websocket.TextMessage, message)
+	if err != nil {
 		log.Printf("[RealtimeClient] Send failed, error: %v\n", err)
 	}
 	return err
@@ -200,8 +406,6 @@ func (r *realtimeClient) FlushVideoFrames() error {
 
 func (r *realtimeClient) sendBatchFramesTo4V(content []map[string]interface{}) error {
 
-	apiURL := "https://open.bigmodel.cn/api/paas/v4/chat/completions"
-
 	requestBody := map[string]interface{}{
 		"model": "glm-4.5v",
 		"messages": []map[string]interface{}{
@@ -220,7 +424,7 @@ func (r *realtimeClient) sendBatchFramesTo4V(content []map[string]interface{}) e
 	}
 
 	// 创建 HTTP 请求
-	req, err := http.NewRequest("POST", apiURL, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequest("POST", ChatCompletionsURL, bytes.NewReader(bodyBytes))
 	if err != nil {
 		log.Printf("[FlushVideoFrames] Failed to create request: %v\n", err)
 		return err
@@ -338,6 +542,100 @@ func (r *realtimeClient) SetInstructions(instructions string) {
 	log.Printf("[RealtimeClient] Instructions set to: %s\n", instructions)
 }
 
+// SetUsageTracker 设置用量统计器，超出预算后会拒绝 response.create 或关闭会话
+func (r *realtimeClient) SetUsageTracker(tracker *usage.Tracker) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.usage = tracker
+}
+
+func (r *realtimeClient) observeUsage(event *events.Event) {
+	r.lock.RLock()
+	tracker := r.usage
+	r.lock.RUnlock()
+	if tracker == nil {
+		return
+	}
+	exceeded := tracker.Observe(event)
+	if exceeded == nil {
+		return
+	}
+	log.Printf("[RealtimeClient] Usage warning: %v\n", exceeded)
+	r.sendFakeEvent(&events.Event{
+		EventID:         fmt.Sprintf("event%d", time.Now().UnixNano()),
+		Type:            events.RealtimeClientUsageBudgetExceededEvent,
+		ClientTimestamp: time.Now().UnixMilli(),
+		Error: &events.EventError{
+			Type:    "budget_exceeded",
+			Code:    string(exceeded.Scope),
+			Message: exceeded.Error(),
+		},
+	})
+	if exceeded.Action == usage.BudgetActionClose {
+		_ = r.Disconnect()
+	}
+}
+
+// subscribe 注册一个内部事件订阅者，在 onReceived 之前按接收顺序调用；
+// 读取循环退出时会以 nil 调用一次，表示连接已断开，读取循环已退出时立即以 nil 调用
+func (r *realtimeClient) subscribe(fn func(event *events.Event)) (unsubscribe func()) {
+	r.subscriberLock.Lock()
+	if r.subscribers == nil {
+		r.subscribers = make(map[uint64]func(event *events.Event))
+	}
+	r.subscriberID++
+	id := r.subscriberID
+	r.subscribers[id] = fn
+	done := r.readerDone
+	r.subscriberLock.Unlock()
+	if done {
+		fn(nil)
+	}
+	return func() {
+		r.subscriberLock.Lock()
+		defer r.subscriberLock.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// dispatchClosed 读取循环退出时以 nil 通知订阅者，连接仍是当前连接时标记 readerDone
+func (r *realtimeClient) dispatchClosed(conn *websocket.Conn) {
+	r.lock.RLock()
+	r.subscriberLock.Lock()
+	if r.conn == conn {
+		r.readerDone = true
+	}
+	fns := r.subscriberFuncs()
+	r.subscriberLock.Unlock()
+	r.lock.RUnlock()
+	for _, fn := range fns {
+		fn(nil)
+	}
+}
+
+func (r *realtimeClient) dispatch(event *events.Event) {
+	r.subscriberLock.Lock()
+	fns := r.subscriberFuncs()
+	r.subscriberLock.Unlock()
+	for _, fn := range fns {
+		fn(event)
+	}
+}
+
+// subscriberFuncs 按注册顺序返回订阅者，调用时需持有 subscriberLock
+func (r *realtimeClient) subscriberFuncs() []func(event *events.Event) {
+	ids := make([]uint64, 0, len(r.subscribers))
+	for id := range r.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	fns := make([]func(event *events.Event), 0, len(ids))
+	for _, id := range ids {
+		fns = append(fns, r.subscribers[id])
+	}
+	return fns
+}
+
 func (r *realtimeClient) sendFakeEvent(event *events.Event) {
 	if r.onReceived != nil {
 		if err := r.onReceived(event); err != nil {
@@ -346,42 +644,50 @@ func (r *realtimeClient) sendFakeEvent(event *events.Event) {
 	}
 }
 
-func (r *realtimeClient) readWsMsg() {
+func (r *realtimeClient) readWsMsg(conn *websocket.Conn) {
 	defer r.wg.Done()
-	deadline := time.Now().Add(waitTimeout)
+	defer r.dispatchClosed(conn)
+	defer r.disconnectConn(conn)
+	r.lock.RLock()
+	protocol, sessionTimeout, idleTimeout := r.protocol, r.sessionTimeout, r.readTimeout
+	r.lock.RUnlock()
+	deadline := time.Now().Add(sessionTimeout)
 	for r.IsConnected() {
-		if time.Now().After(deadline) {
-			log.Printf("[RealtimeClient] ReadWsMsg loop time out after %v", waitTimeout)
+		if sessionTimeout > 0 && time.Now().After(deadline) {
+			log.Printf("[RealtimeClient] ReadWsMsg loop time out after %v", sessionTimeout)
 			return
 		}
 
-		if conn := r.conn; conn != nil {
-			if err := conn.SetReadDeadline(time.Now().Add(15 * time.Second)); err != nil {
+		if idleTimeout > 0 {
+			if err := conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
 				log.Printf("[RealtimeClient] SetReadDeadline failed: %v", err)
 			}
 		}
-		messageType, message, err := r.conn.ReadMessage()
+		messageType, message, err := conn.ReadMessage()
 		if err != nil {
 			log.Printf("[RealtimeClient] Read response failed, type: %d, message: %s, err: %v\n", messageType, string(message), err)
 			return
 		}
 		// log.Printf("[RealtimeClient] Received message type: %d, message len: %d\n", messageType, len(message))
-		if r.onReceived == nil {
-			log.Printf("[RealtimeClient] OnReceived is nil, skipping...\n")
-			continue
-		}
-		event := &events.Event{}
-		if err = json.Unmarshal(message, event); err != nil {
+		event, err := protocol.DecodeServerEvent(message)
+		if err != nil {
 			log.Printf("[RealtimeClient] Unmarshal failed, err: %v\n", err)
 			_ = r.Disconnect()
 			return
 		}
+		r.observeUsage(event)
+		r.dispatch(event)
+		r.dispatchExtension(event, message)
 		// 处理session.update事件，提取instructions
 		if event.Type == "session.update" && event.Session != nil && event.Session.Instructions != "" {
 			r.instructions = event.Session.Instructions
 			log.Printf("[RealtimeClient] Updated instructions: %s\n", r.instructions)
 		}
 
+		if r.onReceived == nil {
+			log.Printf("[RealtimeClient] OnReceived is nil, skipping...\n")
+			continue
+		}
 		if err = r.onReceived(event); err != nil {
 			log.Printf("[RealtimeClient] OnReceived failed, err: %v\n", err)
 			_ = r.Disconnect()
@@ -0,0 +1,444 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+	"github.com/gorilla/websocket"
+)
+
+// fakeServer 模拟 realtime 服务端，收到的客户端事件交给 handle 处理，handle 返回的事件依次发回客户端
+type fakeServer struct {
+	*httptest.Server
+	received chan *events.Event
+}
+
+func newFakeServer(t *testing.T, handle func(event *events.Event) []*events.Event) *fakeServer {
+	t.Helper()
+	s := &fakeServer{received: make(chan *events.Event, 100)}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			event := &events.Event{}
+			if err = json.Unmarshal(message, event); err != nil {
+				return
+			}
+			s.received <- event
+			for _, reply := range handle(event) {
+				if err = conn.WriteMessage(websocket.TextMessage, []byte(reply.ToJson())); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http")
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestSendText(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event {
+		if event.Type != events.RealtimeClientEventResponseCreate {
+			return nil
+		}
+		return []*events.Event{
+			{Type: events.RealtimeServerEventResponseCreated, Response: &events.Response{ID: "resp_1"}},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: "resp_1", Delta: "你好"},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: "resp_other", Delta: "ignored"},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: "resp_1", Delta: "，世界"},
+			{Type: events.RealtimeServerEventResponseTextDone, ResponseID: "resp_1", Text: strPtr("你好，世界")},
+			{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_1", Usage: &events.Usage{TotalTokens: 12}}},
+		}
+	})
+	c := NewRealtimeClient(server.URL(), "", nil)
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := c.SendText(ctx, "你好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deltas []string
+	for {
+		delta, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		deltas = append(deltas, delta)
+	}
+	if strings.Join(deltas, "|") != "你好|，世界" || stream.Text() != "你好，世界" {
+		t.Fatalf("unexpected stream result: %q, %q", deltas, stream.Text())
+	}
+	if usage := stream.Usage(); usage == nil || usage.TotalTokens != 12 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+
+	item, create := <-server.received, <-server.received
+	if item.Type != events.RealtimeClientEventConversationItemCreate || *item.Item.Content[0].Text != "你好" {
+		t.Fatalf("unexpected item event: %s", item.ToJson())
+	}
+	if create.Response == nil || len(create.Response.Modalities) != 1 || create.Response.Modalities[0] != events.ModalityText {
+		t.Fatalf("unexpected response.create: %s", create.ToJson())
+	}
+}
+
+// TestSendTextIgnoresForeignResponses 其他请求或服务端 VAD 创建的响应与本次响应交错时，文本流只返回本次响应的内容
+func TestSendTextIgnoresForeignResponses(t *testing.T) {
+	round := 0
+	server := newFakeServer(t, func(event *events.Event) []*events.Event {
+		if event.Type != events.RealtimeClientEventResponseCreate {
+			return nil
+		}
+		round++
+		own := fmt.Sprintf("resp_%d", round)
+		foreign := &events.Response{ID: "resp_other_" + own, Metadata: map[string]string{textStreamMetadataKey: "other"}}
+		if round == 2 {
+			// 服务端 VAD 触发的响应没有 metadata
+			foreign.Metadata = nil
+		}
+		return []*events.Event{
+			// 其他请求引发的错误不影响文本流
+			{Type: events.RealtimeServerEventError, Error: &events.EventError{Code: "invalid_value", Message: "other", EventID: "evt_other"}},
+			{Type: events.RealtimeServerEventResponseCreated, Response: foreign},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: foreign.ID, Delta: "foreign"},
+			{Type: events.RealtimeServerEventResponseCreated, Response: &events.Response{ID: own, Metadata: event.Response.Metadata}},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: own, Delta: own},
+			{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: foreign.ID}},
+			{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: own, Delta: "!"},
+			{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: own}},
+		}
+	})
+	c := NewRealtimeClient(server.URL(), "", nil)
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, want := range []string{"resp_1!", "resp_2!"} {
+		stream, err := c.SendText(ctx, "你好")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var text string
+		for {
+			delta, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			text += delta
+		}
+		if text != want || stream.ResponseID() != strings.TrimSuffix(want, "!") {
+			t.Fatalf("unexpected stream result: %q from %s", text, stream.ResponseID())
+		}
+	}
+}
+
+func TestSendTextErrors(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event {
+		if event.Type != events.RealtimeClientEventResponseCreate {
+			return nil
+		}
+		return []*events.Event{{Type: events.RealtimeServerEventError, Error: &events.EventError{Code: "rate_limited", Message: "slow down", EventID: event.EventID}}}
+	})
+	c := NewRealtimeClient(server.URL(), "", nil)
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	stream, err := c.SendText(context.Background(), "你好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err == nil || !strings.Contains(err.Error(), "rate_limited") {
+		t.Fatalf("expected error for this request, got %v", err)
+	}
+
+	// 没有事件时 RecvContext 在 ctx 结束后返回
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	stream = newTextStream(&c.metadataEchoed)
+	if _, err := stream.RecvContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestSubscribeAfterReadLoopExit(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event { return nil })
+	c := NewRealtimeClient(server.URL(), "", nil)
+	c.readTimeout = 50 * time.Millisecond
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	c.Wait()
+	closed := 0
+	unsubscribe := c.subscribe(func(event *events.Event) {
+		if event == nil {
+			closed++
+		}
+	})
+	defer unsubscribe()
+	if closed != 1 {
+		t.Fatalf("expected one nil notification, got %d", closed)
+	}
+	if _, err := c.SendText(context.Background(), "你好"); err == nil {
+		t.Fatal("expected SendText to fail after the read loop exits")
+	}
+}
+
+func TestSendValidatesEvents(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event { return nil })
+	c := NewRealtimeClient(server.URL(), "", nil)
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	err := c.Send(&events.Event{Type: events.RealtimeClientEventConversationItemTruncate})
+	var validationErr *events.ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "item_id" {
+		t.Fatalf("expected validation error for item_id, got %v", err)
+	}
+	err = c.Send(&events.Event{
+		Type: events.RealtimeClientEventConversationItemCreate,
+		Item: &events.Item{Type: events.ItemTypeMessage, Role: "robot", Content: []events.Content{{Type: events.ContentTypeInputText}}},
+	})
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 2 {
+		t.Fatalf("expected role and text errors, got %v", err)
+	}
+
+	c.SetValidateEvents(false)
+	if err := c.Send(&events.Event{Type: events.RealtimeClientEventInputAudioBufferAppend}); err != nil {
+		t.Fatal(err)
+	}
+	if received := <-server.received; received.Type != events.RealtimeClientEventInputAudioBufferAppend {
+		t.Fatalf("unexpected event: %s", received.ToJson())
+	}
+}
+
+func TestTranscriptionClient(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event {
+		switch event.Type {
+		case events.RealtimeClientEventTranscriptionSessionUpdate:
+			return []*events.Event{{Type: events.RealtimeServerEventTranscriptionSessionUpdated, Session: event.Session}}
+		case events.RealtimeClientEventInputAudioBufferCommit:
+			// 第二段语音的结果先于第一段到达
+			return []*events.Event{
+				{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "a", AudioStartMS: 100},
+				{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "a", AudioEndMS: 900},
+				{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "b", AudioStartMS: 1200},
+				{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta, ItemID: "b", Delta: "世界"},
+				{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta, ItemID: "a", Delta: "你"},
+				{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta, ItemID: "a", Delta: "好"},
+				{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "b", Transcript: strPtr("世界")},
+				{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "a", Transcript: strPtr("你好")},
+			}
+		}
+		return nil
+	})
+	c := NewTranscriptionClient(server.URL(), "", TranscriptionConfig{
+		Model:         "glm-asr",
+		Language:      "zh",
+		TurnDetection: events.Null[events.TurnDetection](),
+	})
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	update := <-server.received
+	if tr, ok := update.Session.InputAudioTranscription.Get(); !ok || tr.Model != "glm-asr" || tr.Language != "zh" || !update.Session.TurnDetection.IsNull() {
+		t.Fatalf("unexpected transcription_session.update: %s", update.ToJson())
+	}
+	if err := c.AppendAudio([]byte{0, 1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for len(got) < 5 {
+		tr, err := c.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s:%s:%v:%d-%d", tr.Index, tr.ItemID, tr.Text, tr.Final, tr.AudioStartMS, tr.AudioEndMS))
+	}
+	want := []string{
+		"0:a:你:false:100-900",
+		"0:a:你好:false:100-900",
+		"0:a:你好:true:100-900",
+		"1:b:世界:false:1200-0",
+		"1:b:世界:true:1200-0",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected transcripts:\n got %q\nwant %q", got, want)
+	}
+	if c.Session() == nil {
+		t.Error("session should be updated")
+	}
+	c.mu.Lock()
+	tracked := len(c.segments) + len(c.order)
+	c.mu.Unlock()
+	if tracked != 0 {
+		t.Errorf("finished segments should not be tracked, got %d", tracked)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Recv(); err != io.EOF {
+		t.Fatalf("expected io.EOF after Close, got %v", err)
+	}
+
+	// Close 后可以重新连接，序号重新从 0 开始
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if tr, err := c.Recv(); err != nil || tr.Index != 0 || tr.ItemID != "a" {
+		t.Fatalf("unexpected transcript after reconnect: %+v, %v", tr, err)
+	}
+}
+
+func TestReadLoopExitDisconnects(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event { return nil })
+	c := NewRealtimeClient(server.URL(), "", nil)
+	c.readTimeout = 50 * time.Millisecond
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	c.Wait()
+	if c.IsConnected() {
+		t.Fatal("client should be disconnected after the read loop exits")
+	}
+	if err := c.Send(&events.Event{Type: events.RealtimeClientEventInputAudioBufferClear}); err == nil {
+		t.Fatal("expected error sending on a closed connection")
+	}
+}
+
+// TestTranscriptionClientSkipsStuckSegments 没有转写结果的语音段不会一直阻塞后面的结果
+func TestTranscriptionClientSkipsStuckSegments(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event {
+		if event.Type != events.RealtimeClientEventInputAudioBufferCommit {
+			return nil
+		}
+		return []*events.Event{
+			// 被 VAD 丢弃的噪音，之后的语音段提交时放弃
+			{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "noise"},
+			// a 已提交但一直没有转写结果，超时后放弃
+			{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "a"},
+			{Type: events.RealtimeServerEventInputAudioBufferCommitted, ItemID: "a"},
+			{Type: events.RealtimeServerEventInputAudioBufferCommitted, ItemID: "b"},
+			{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta, ItemID: "b", Delta: "世界"},
+			{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "b", Transcript: strPtr("世界")},
+			// c 未提交就被清空
+			{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "c"},
+			{Type: events.RealtimeServerEventInputAudioBufferCleared},
+			// d 返回部分结果后被删除
+			{Type: events.RealtimeServerEventInputAudioBufferCommitted, ItemID: "d"},
+			{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta, ItemID: "d", Delta: "半"},
+			{Type: events.RealtimeServerEventConversationItemDeleted, ItemID: "d"},
+			{Type: events.RealtimeServerEventInputAudioBufferCommitted, ItemID: "e"},
+			{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "e", Transcript: strPtr("好")},
+		}
+	})
+	c := NewTranscriptionClient(server.URL(), "", TranscriptionConfig{
+		TurnDetection:  events.Null[events.TurnDetection](),
+		SegmentTimeout: 200 * time.Millisecond,
+	})
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for len(got) < 6 {
+		tr, err := c.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s:%s:%v:%v", tr.Index, tr.ItemID, tr.Text, tr.Final, tr.Err))
+	}
+	want := []string{
+		"0:a::true:transcription timed out",
+		"1:b:世界:false:<nil>",
+		"1:b:世界:true:<nil>",
+		"2:d:半:false:<nil>",
+		"2:d:半:true:transcript dropped",
+		"3:e:好:true:<nil>",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected transcripts:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSendAudio(t *testing.T) {
+	server := newFakeServer(t, func(event *events.Event) []*events.Event { return nil })
+	c := NewRealtimeClient(server.URL(), "", nil)
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Disconnect()
+
+	audio := []byte{0, 1, 2, 3, 4}
+	if err := c.SendAudio(audio); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendEvent(events.InputAudioBufferAppendEvent{EventID: "evt_1", Audio: audio}); err != nil {
+		t.Fatal(err)
+	}
+	for _, eventID := range []string{"", "evt_1"} {
+		event := <-server.received
+		if event.Type != events.RealtimeClientEventInputAudioBufferAppend || event.EventID != eventID ||
+			event.Audio != "AAECAwQ=" || event.ClientTimestamp <= 0 {
+			t.Fatalf("unexpected audio event: %s", event.ToJson())
+		}
+	}
+
+	var validationErr *events.ValidationError
+	if err := c.SendAudio(nil); !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
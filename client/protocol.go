@@ -0,0 +1,54 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Protocol 在 SDK 的事件模型与服务端的线上协议之间转换，默认直接使用 GLM Realtime 协议。
+// 接入其他兼容服务时可通过 SetProtocol 替换，例如 openai.NewProtocol
+type Protocol interface {
+	// Header 返回建立 websocket 连接时的请求头
+	Header(apiKey string) http.Header
+	// EncodeClientEvent 将客户端事件编码为发送给服务端的消息
+	EncodeClientEvent(event *events.Event) ([]byte, error)
+	// DecodeServerEvent 将服务端消息解析为事件
+	DecodeServerEvent(data []byte) (*events.Event, error)
+}
+
+type glmProtocol struct{}
+
+func (glmProtocol) Header(apiKey string) http.Header {
+	if apiKey == "" {
+		return nil
+	}
+	header := make(http.Header)
+	header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	return header
+}
+
+func (glmProtocol) EncodeClientEvent(event *events.Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func (glmProtocol) DecodeServerEvent(data []byte) (*events.Event, error) {
+	event := &events.Event{}
+	// 直接调用 UnmarshalJSON，避免 json.Unmarshal 在调用前再校验一遍整条消息
+	if err := event.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// SetProtocol 设置线上协议，需在 Connect 之前调用，传入 nil 时恢复为 GLM Realtime 协议
+func (r *realtimeClient) SetProtocol(protocol Protocol) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if protocol == nil {
+		protocol = glmProtocol{}
+	}
+	r.protocol = protocol
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// TextStream SendText 返回的文本增量流，Recv 读完所有增量后返回 io.EOF，
+// 此时可通过 Text 和 Usage 获取最终文本和用量
+type TextStream struct {
+	unsubscribe func()
+	// tag 写入 response.create 的 metadata，用于从 response.created 中识别本次请求的响应，
+	// 同时作为所发事件 event_id 的前缀，用于识别本次请求引发的 error
+	tag    string
+	echoed *atomic.Bool
+
+	mu         sync.Mutex
+	notify     chan struct{}
+	done       chan struct{}
+	deltas     []string
+	finished   bool
+	err        error
+	sent       bool
+	responseID string
+	builder    strings.Builder
+	text       *string
+	usage      *events.Usage
+}
+
+// textStreamMetadataKey SendText 在 response.create 的 metadata 中使用的键
+const textStreamMetadataKey = "text_stream_id"
+
+func newTextStream(echoed *atomic.Bool) *TextStream {
+	return &TextStream{
+		tag:    fmt.Sprintf("text%d", time.Now().UnixNano()),
+		echoed: echoed,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// SendText 以纯文本模式发送一条用户消息并触发仅包含 text 模态的 response.create，
+// ctx 取消时会发送 response.cancel 并结束文本流。
+// 文本流只跟踪 metadata 与本次请求匹配的响应，发送前已开始的响应（如服务端 VAD、工具调用自动回复）会被忽略；
+// 服务端不返回 metadata 时退化为发送后收到的第一个响应
+func (r *realtimeClient) SendText(ctx context.Context, text string) (*TextStream, error) {
+	stream := newTextStream(&r.metadataEchoed)
+	unsubscribe := r.subscribe(stream.observe)
+	stream.mu.Lock()
+	stream.unsubscribe = unsubscribe
+	finished, err := stream.finished, stream.err
+	stream.mu.Unlock()
+	if finished {
+		// 连接已断开，订阅时已收到 nil
+		unsubscribe()
+		return nil, err
+	}
+
+	content := text
+	if err := r.Send(&events.Event{
+		EventID: stream.tag + "_item",
+		Type:    events.RealtimeClientEventConversationItemCreate,
+		Item: &events.Item{
+			Object:  events.ItemObjectRealTimeItem,
+			Type:    events.ItemTypeMessage,
+			Status:  events.ItemStatusCompleted,
+			Role:    events.ItemRoleUser,
+			Content: []events.Content{{Type: events.ContentTypeInputText, Text: &content}},
+		},
+	}); err != nil {
+		stream.Close()
+		return nil, err
+	}
+	stream.mu.Lock()
+	stream.sent = true
+	stream.mu.Unlock()
+	if err := r.Send(&events.Event{
+		EventID: stream.tag + "_response",
+		Type:    events.RealtimeClientEventResponseCreate,
+		Response: &events.Response{
+			Modalities: []events.Modality{events.ModalityText},
+			Metadata:   map[string]string{textStreamMetadataKey: stream.tag},
+		},
+	}); err != nil {
+		stream.Close()
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-stream.done:
+		case <-ctx.Done():
+			if responseID := stream.ResponseID(); responseID != "" {
+				_ = r.Send(&events.Event{Type: events.RealtimeClientEventResponseCancel, ResponseID: responseID})
+			}
+			stream.finish(ctx.Err())
+		}
+	}()
+	return stream, nil
+}
+
+// Recv 阻塞直到有新的文本增量，文本流结束时返回 io.EOF，出错时返回对应错误
+func (s *TextStream) Recv() (string, error) {
+	return s.RecvContext(context.Background())
+}
+
+// RecvContext 与 Recv 相同，ctx 取消时返回 ctx.Err()，文本流不会结束，之后仍可继续读取
+func (s *TextStream) RecvContext(ctx context.Context) (string, error) {
+	for {
+		s.mu.Lock()
+		if len(s.deltas) > 0 {
+			delta := s.deltas[0]
+			s.deltas = s.deltas[1:]
+			s.mu.Unlock()
+			return delta, nil
+		}
+		if s.finished {
+			err := s.err
+			s.mu.Unlock()
+			if err == nil {
+				err = io.EOF
+			}
+			return "", err
+		}
+		s.mu.Unlock()
+		select {
+		case <-s.notify:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// Text 返回最终文本，文本流结束前返回已收到的部分
+func (s *TextStream) Text() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.text != nil {
+		return *s.text
+	}
+	return s.builder.String()
+}
+
+// Usage 返回 response.done 中的用量，文本流结束前为 nil
+func (s *TextStream) Usage() *events.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usage
+}
+
+// ResponseID 返回本次请求创建的响应 ID，收到对应的 response.created 之前为空
+func (s *TextStream) ResponseID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.responseID
+}
+
+// Close 停止接收后续事件，已收到的增量仍可通过 Recv 读取
+func (s *TextStream) Close() {
+	s.finish(nil)
+}
+
+func (s *TextStream) observe(event *events.Event) {
+	if event == nil {
+		s.finish(fmt.Errorf("connection closed"))
+		return
+	}
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	if s.responseID == "" {
+		switch event.Type {
+		case events.RealtimeServerEventResponseCreated:
+			if s.matches(event) {
+				s.responseID = event.ResponseID
+				if event.Response != nil && event.Response.ID != "" {
+					s.responseID = event.Response.ID
+				}
+			}
+		case events.RealtimeServerEventError:
+			// 只处理本次请求引发的错误，其他请求的错误不影响文本流
+			if event.Error != nil && strings.HasPrefix(event.Error.EventID, s.tag+"_") {
+				s.mu.Unlock()
+				s.finish(fmt.Errorf("%s: %s", event.Error.Code, event.Error.Message))
+				return
+			}
+		}
+		s.mu.Unlock()
+		return
+	}
+	responseID := event.ResponseID
+	if event.Response != nil && event.Response.ID != "" {
+		responseID = event.Response.ID
+	}
+	if responseID != s.responseID {
+		s.mu.Unlock()
+		return
+	}
+	switch event.Type {
+	case events.RealtimeServerEventResponseTextDelta:
+		s.builder.WriteString(event.Delta)
+		s.deltas = append(s.deltas, event.Delta)
+	case events.RealtimeServerEventResponseTextDone:
+		if event.Text != nil {
+			text := *event.Text
+			s.text = &text
+		}
+	case events.RealtimeServerEventResponseDone:
+		if event.Response != nil {
+			s.usage = event.Response.Usage
+		}
+		s.mu.Unlock()
+		s.finish(nil)
+		return
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+// matches 判断 response.created 是否是本次 response.create 创建的响应
+func (s *TextStream) matches(event *events.Event) bool {
+	if !s.sent {
+		return false
+	}
+	var tag string
+	if event.Response != nil {
+		tag = event.Response.Metadata[textStreamMetadataKey]
+	}
+	switch {
+	case tag == s.tag:
+		s.echoed.Store(true)
+		return true
+	case tag != "":
+		return false
+	default:
+		// 服务端会返回 metadata 时，没有标记的响应不是本次请求创建的
+		return !s.echoed.Load()
+	}
+}
+
+func (s *TextStream) finish(err error) {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished, s.err = true, err
+	close(s.done)
+	unsubscribe := s.unsubscribe
+	s.mu.Unlock()
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+	s.signal()
+}
+
+func (s *TextStream) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
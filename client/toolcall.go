@@ -0,0 +1,68 @@
+package client
+
+import (
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/toolcall"
+)
+
+// SetToolRegistry 设置工具注册表：发送 session.update 时自动附带注册的工具定义，
+// 收到函数调用时自动调用对应工具并发回结果，autoResponse 为 true 时随后触发 response.create。
+// 传 nil 取消自动调用
+func (r *realtimeClient) SetToolRegistry(registry *toolcall.Registry, autoResponse bool) {
+	r.toolLock.Lock()
+	defer r.toolLock.Unlock()
+	if r.unsubscribeTools != nil {
+		r.unsubscribeTools()
+		r.unsubscribeTools = nil
+		r.toolDispatcher.Cancel()
+	}
+	r.toolRegistry, r.toolDispatcher = registry, nil
+	if registry == nil {
+		return
+	}
+	r.toolDispatcher = toolcall.NewDispatcher(registry, r)
+	r.toolDispatcher.SetAutoResponse(autoResponse)
+	r.toolDispatcher.SetTimeout(r.toolTimeout)
+	r.unsubscribeTools = r.subscribe(r.toolDispatcher.Observe)
+}
+
+// SetToolTimeout 设置工具调用的默认超时时间，默认为 toolcall.DefaultTimeout，小于等于 0 表示不限制。
+// 可在 SetToolRegistry 之前或之后调用，更换注册表后仍然生效
+func (r *realtimeClient) SetToolTimeout(timeout time.Duration) {
+	r.toolLock.Lock()
+	defer r.toolLock.Unlock()
+	r.toolTimeout = timeout
+	if r.toolDispatcher != nil {
+		r.toolDispatcher.SetTimeout(timeout)
+	}
+}
+
+// cancelToolCalls 发送 response.cancel 时取消进行中的工具调用
+func (r *realtimeClient) cancelToolCalls(event *events.Event) {
+	if event.Type != events.RealtimeClientEventResponseCancel {
+		return
+	}
+	r.toolLock.Lock()
+	dispatcher := r.toolDispatcher
+	r.toolLock.Unlock()
+	if dispatcher != nil {
+		// 取消后会发回错误结果，不能在 Send 持有锁时同步进行
+		go dispatcher.Cancel()
+	}
+}
+
+// withRegisteredTools 在 session.update 中合并注册表中的工具定义
+func (r *realtimeClient) withRegisteredTools(event *events.Event) {
+	if event.Type != events.RealtimeClientEventSessionUpdate || event.Session == nil {
+		return
+	}
+	r.toolLock.Lock()
+	registry := r.toolRegistry
+	r.toolLock.Unlock()
+	if registry == nil {
+		return
+	}
+	event.Session.Tools = registry.MergeTools(event.Session.Tools)
+}
@@ -0,0 +1,444 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// TranscriptionConfig 纯转写会话的配置
+type TranscriptionConfig struct {
+	Model    string
+	Language string // ISO-639-1 语言代码，如 zh，为空时自动识别
+	Prompt   string
+	// InputAudioFormat 输入音频格式，默认 pcm
+	InputAudioFormat string
+	// TurnDetection 未设置时使用服务端默认的 VAD，设置为 events.Null 时需调用 Commit 手动切分语音
+	TurnDetection  events.Optional[events.TurnDetection]
+	NoiseReduction events.Optional[events.NoiseReduction]
+	// SegmentTimeout 语音段提交后等待转写结果的最长时间，超时后放弃该段，后面的结果不再被阻塞。
+	// 为 0 时使用 DefaultSegmentTimeout，小于 0 表示一直等待
+	SegmentTimeout time.Duration
+}
+
+// DefaultSegmentTimeout 语音段提交后等待转写结果的默认时间
+const DefaultSegmentTimeout = 30 * time.Second
+
+var (
+	// ErrTranscriptionTimeout 语音段提交后超过 SegmentTimeout 仍未收到转写结果
+	ErrTranscriptionTimeout = errors.New("transcription timed out")
+	// ErrTranscriptDropped 已返回部分结果的语音段被清空或删除
+	ErrTranscriptDropped = errors.New("transcript dropped")
+)
+
+func (c TranscriptionConfig) segmentTimeout() time.Duration {
+	switch {
+	case c.SegmentTimeout == 0:
+		return DefaultSegmentTimeout
+	case c.SegmentTimeout < 0:
+		return 0
+	}
+	return c.SegmentTimeout
+}
+
+func (c TranscriptionConfig) session() *events.Session {
+	format := c.InputAudioFormat
+	if format == "" {
+		format = "pcm"
+	}
+	return &events.Session{
+		InputAudioFormat: format,
+		InputAudioTranscription: events.Some(events.InputAudioTranscription{
+			Enabled:  true,
+			Model:    c.Model,
+			Language: c.Language,
+			Prompt:   c.Prompt,
+		}),
+		TurnDetection:            c.TurnDetection,
+		InputAudioNoiseReduction: c.NoiseReduction,
+	}
+}
+
+// Transcript 一段语音的转写结果。每段语音先产生若干 Final 为 false 的部分结果，最后产生一个 Final 为 true 的最终结果
+type Transcript struct {
+	ItemID string
+	// Index 语音段序号，从 0 开始按音频顺序连续递增，Recv 按该顺序返回结果；没有结果就被丢弃的语音段不占用序号
+	Index int
+	// Delta 本次新增的文本，最终结果中为空
+	Delta string
+	// Text 目前为止的完整文本
+	Text  string
+	Final bool
+	// Err 转写失败的原因，仅出现在最终结果中，超时或被丢弃时为 ErrTranscriptionTimeout、ErrTranscriptDropped
+	Err error
+	// AudioStartMS、AudioEndMS 语音在输入音频中的起止时间，来自服务端 VAD 事件，未知时为 0
+	AudioStartMS int64
+	AudioEndMS   int64
+	ReceivedAt   time.Time
+}
+
+type transcriptSegment struct {
+	itemID string
+	// index 首次返回结果时分配，之前为 -1
+	index          int
+	text           string
+	startMS, endMS int64
+	// committed 音频已提交，之后会有转写结果；updated 为最近一次收到该段事件的时间，用于超时判断
+	committed bool
+	updated   time.Time
+	done      bool
+	pending   []Transcript
+}
+
+// TranscriptionClient 纯语音转文字客户端，通过 transcription_session.update 配置会话，
+// 持续写入音频，并按语音顺序返回部分和最终转写结果。连接在 Close 或服务端断开前一直保持，不受 RealtimeClient 读取超时的限制；
+// Close 后可再次 Connect，重新连接会丢弃尚未读取的结果
+type TranscriptionClient struct {
+	rt          *realtimeClient
+	unsubscribe func()
+
+	mu       sync.Mutex
+	notify   chan struct{}
+	config   TranscriptionConfig
+	session  *events.Session
+	segments map[string]*transcriptSegment
+	order    []*transcriptSegment
+	nextIdx  int
+	ready    []Transcript
+	errs     []error
+	finished bool
+	err      error
+}
+
+func NewTranscriptionClient(url, apiKey string, config TranscriptionConfig) *TranscriptionClient {
+	c := &TranscriptionClient{
+		notify:   make(chan struct{}, 1),
+		config:   config,
+		segments: make(map[string]*transcriptSegment),
+	}
+	c.rt = NewRealtimeClient(url, apiKey, func(event *events.Event) error { return nil })
+	// 静音时服务端可能长时间没有消息，连接由 Close 关闭
+	c.rt.sessionTimeout, c.rt.readTimeout = 0, 0
+	return c
+}
+
+// SetProtocol 设置线上协议，需在 Connect 之前调用
+func (c *TranscriptionClient) SetProtocol(protocol Protocol) {
+	c.rt.SetProtocol(protocol)
+}
+
+// Connect 建立连接并发送转写会话配置
+func (c *TranscriptionClient) Connect() error {
+	c.rt.waitReader()
+	if err := c.rt.Connect(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.finished {
+		c.reset()
+	}
+	subscribed, config := c.unsubscribe != nil, c.config
+	c.mu.Unlock()
+	if !subscribed {
+		// 连接已断开时 subscribe 会立即调用 observe，不能持有 mu
+		unsubscribe := c.rt.subscribe(c.observe)
+		c.mu.Lock()
+		finished, err := c.finished, c.err
+		if !finished {
+			c.unsubscribe = unsubscribe
+		}
+		c.mu.Unlock()
+		if finished {
+			unsubscribe()
+			return err
+		}
+	}
+	return c.Configure(config)
+}
+
+// Configure 更新转写会话配置，服务端确认后可通过 Session 获取生效的配置
+func (c *TranscriptionClient) Configure(config TranscriptionConfig) error {
+	c.mu.Lock()
+	c.config = config
+	c.mu.Unlock()
+	return c.rt.Send(&events.Event{
+		Type:    events.RealtimeClientEventTranscriptionSessionUpdate,
+		Session: config.session(),
+	})
+}
+
+// Session 返回服务端最近一次 transcription_session.updated 中的会话配置，尚未确认时为 nil
+func (c *TranscriptionClient) Session() *events.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
+}
+
+// AppendAudio 写入一段音频，格式需与配置的 InputAudioFormat 一致
+func (c *TranscriptionClient) AppendAudio(audio []byte) error {
+	return c.rt.SendAudio(audio)
+}
+
+// Commit 提交已写入的音频作为一段语音，关闭服务端 VAD 时使用
+func (c *TranscriptionClient) Commit() error {
+	return c.rt.Send(&events.Event{Type: events.RealtimeClientEventInputAudioBufferCommit})
+}
+
+// Clear 丢弃尚未提交的音频
+func (c *TranscriptionClient) Clear() error {
+	return c.rt.Send(&events.Event{Type: events.RealtimeClientEventInputAudioBufferClear})
+}
+
+// Recv 阻塞直到有新的转写结果，结果按语音顺序返回：后一段语音的结果会等前一段的最终结果返回后再返回，
+// 前一段超过 SegmentTimeout 没有结果时以 ErrTranscriptionTimeout 结束。
+// 服务端 error 事件以错误形式返回，之后仍可继续调用；连接关闭且结果读完后返回 io.EOF 或连接错误
+func (c *TranscriptionClient) Recv() (Transcript, error) {
+	for {
+		c.mu.Lock()
+		if !c.finished {
+			c.flush(time.Now(), false)
+		}
+		if len(c.errs) > 0 {
+			err := c.errs[0]
+			c.errs = c.errs[1:]
+			c.mu.Unlock()
+			return Transcript{}, err
+		}
+		if len(c.ready) > 0 {
+			t := c.ready[0]
+			c.ready = c.ready[1:]
+			c.mu.Unlock()
+			return t, nil
+		}
+		if c.finished {
+			err := c.err
+			c.mu.Unlock()
+			if err == nil {
+				err = io.EOF
+			}
+			return Transcript{}, err
+		}
+		wait, ok := c.headDeadline()
+		c.mu.Unlock()
+		if !ok {
+			<-c.notify
+			continue
+		}
+		timer := time.NewTimer(time.Until(wait))
+		select {
+		case <-c.notify:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// headDeadline 返回最前面的已提交语音段的超时时间
+func (c *TranscriptionClient) headDeadline() (time.Time, bool) {
+	timeout := c.config.segmentTimeout()
+	if len(c.order) == 0 || timeout <= 0 || !c.order[0].committed {
+		return time.Time{}, false
+	}
+	return c.order[0].updated.Add(timeout), true
+}
+
+// Close 断开连接，已收到的结果仍可通过 Recv 读取
+func (c *TranscriptionClient) Close() error {
+	c.finish(nil)
+	return c.rt.Disconnect()
+}
+
+func (c *TranscriptionClient) observe(event *events.Event) {
+	if event == nil {
+		c.finish(fmt.Errorf("connection closed"))
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	if c.finished {
+		c.mu.Unlock()
+		return
+	}
+	switch event.Type {
+	case events.RealtimeServerEventTranscriptionSessionUpdated:
+		c.session = event.Session
+	case events.RealtimeServerEventInputAudioBufferSpeechStarted:
+		if seg := c.segment(event.ItemID, now); seg != nil {
+			seg.startMS = event.AudioStartMS
+		}
+	case events.RealtimeServerEventInputAudioBufferSpeechStopped:
+		if seg := c.segment(event.ItemID, now); seg != nil {
+			seg.endMS = event.AudioEndMS
+		}
+	case events.RealtimeServerEventInputAudioBufferCommitted:
+		if seg := c.segment(event.ItemID, now); seg != nil {
+			c.commit(seg)
+		}
+	case events.RealtimeServerEventConversationItemCreated:
+		// 条目在提交之后创建，可能晚于转写结果，只标记已有的语音段，避免为已返回结果的条目重新建段
+		if event.Item != nil {
+			if seg := c.lookup(event.Item.ID, now); seg != nil {
+				c.commit(seg)
+			}
+		}
+	case events.RealtimeServerEventInputAudioBufferCleared:
+		// 未提交的语音不会再有转写结果
+		for _, seg := range c.order {
+			if !seg.committed {
+				c.drop(seg, now)
+			}
+		}
+	case events.RealtimeServerEventConversationItemDeleted:
+		if seg, ok := c.segments[event.ItemID]; ok {
+			c.drop(seg, now)
+		}
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta:
+		if seg := c.lookup(event.ItemID, now); seg != nil && !seg.done && event.Delta != "" {
+			seg.committed = true
+			seg.text += event.Delta
+			seg.pending = append(seg.pending, Transcript{Delta: event.Delta, Text: seg.text, ReceivedAt: now})
+		}
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted:
+		if seg := c.lookup(event.ItemID, now); seg != nil && !seg.done {
+			seg.committed = true
+			if event.Transcript != nil {
+				seg.text = *event.Transcript
+			}
+			seg.done = true
+			seg.pending = append(seg.pending, Transcript{Text: seg.text, Final: true, ReceivedAt: now})
+		}
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionFailed:
+		if seg := c.lookup(event.ItemID, now); seg != nil && !seg.done {
+			seg.done = true
+			seg.pending = append(seg.pending, Transcript{Text: seg.text, Final: true, Err: eventError(event), ReceivedAt: now})
+		}
+	case events.RealtimeServerEventError:
+		c.errs = append(c.errs, eventError(event))
+	}
+	c.flush(now, false)
+	c.mu.Unlock()
+	c.signal()
+}
+
+// segment 返回语音段，首次出现的 item 按到达顺序排列
+func (c *TranscriptionClient) segment(itemID string, now time.Time) *transcriptSegment {
+	if itemID == "" {
+		return nil
+	}
+	seg, ok := c.segments[itemID]
+	if !ok {
+		seg = &transcriptSegment{itemID: itemID, index: -1}
+		c.segments[itemID] = seg
+		c.order = append(c.order, seg)
+	}
+	seg.updated = now
+	return seg
+}
+
+// lookup 返回已有的语音段。转写事件总在提交之后到达，找不到说明该段已返回最终结果或已被删除，之后的事件忽略
+func (c *TranscriptionClient) lookup(itemID string, now time.Time) *transcriptSegment {
+	seg, ok := c.segments[itemID]
+	if !ok {
+		return nil
+	}
+	seg.updated = now
+	return seg
+}
+
+// commit 标记语音段已提交。音频按顺序提交，排在前面仍未提交的语音段已被服务端丢弃（如 VAD 判定为噪音），不会再有结果
+func (c *TranscriptionClient) commit(seg *transcriptSegment) {
+	if seg.committed {
+		return
+	}
+	seg.committed = true
+	for _, prev := range c.order {
+		if prev == seg {
+			break
+		}
+		if !prev.committed {
+			c.drop(prev, seg.updated)
+		}
+	}
+}
+
+// drop 放弃语音段，已返回过部分结果时补一个带 ErrTranscriptDropped 的最终结果
+func (c *TranscriptionClient) drop(seg *transcriptSegment, now time.Time) {
+	if seg.done {
+		return
+	}
+	seg.done = true
+	if seg.index >= 0 || len(seg.pending) > 0 {
+		seg.pending = append(seg.pending, Transcript{Text: seg.text, Final: true, Err: ErrTranscriptDropped, ReceivedAt: now})
+	}
+}
+
+// flush 将最前面的语音段的结果移入 ready，该段结束或超时后再处理下一段；all 为 true 时不再等待未结束的语音段
+func (c *TranscriptionClient) flush(now time.Time, all bool) {
+	for len(c.order) > 0 {
+		seg := c.order[0]
+		if deadline, ok := c.headDeadline(); ok && !seg.done && !now.Before(deadline) {
+			seg.done = true
+			seg.pending = append(seg.pending, Transcript{Text: seg.text, Final: true, Err: ErrTranscriptionTimeout, ReceivedAt: now})
+		}
+		if len(seg.pending) > 0 && seg.index < 0 {
+			seg.index = c.nextIdx
+			c.nextIdx++
+		}
+		for _, t := range seg.pending {
+			t.ItemID, t.Index = seg.itemID, seg.index
+			t.AudioStartMS, t.AudioEndMS = seg.startMS, seg.endMS
+			c.ready = append(c.ready, t)
+		}
+		seg.pending = nil
+		if !seg.done && !all {
+			return
+		}
+		// 结果已全部返回，不再跟踪该段
+		c.order = c.order[1:]
+		delete(c.segments, seg.itemID)
+	}
+}
+
+// reset 清空上一个连接的状态
+func (c *TranscriptionClient) reset() {
+	c.segments = make(map[string]*transcriptSegment)
+	c.order, c.ready, c.errs = nil, nil, nil
+	c.nextIdx = 0
+	c.session = nil
+	c.finished, c.err = false, nil
+}
+
+func (c *TranscriptionClient) finish(err error) {
+	c.mu.Lock()
+	if c.finished {
+		c.mu.Unlock()
+		return
+	}
+	c.flush(time.Now(), true)
+	c.finished, c.err = true, err
+	unsubscribe := c.unsubscribe
+	c.unsubscribe = nil
+	c.mu.Unlock()
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+	c.signal()
+}
+
+func (c *TranscriptionClient) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func eventError(event *events.Event) error {
+	if event.Error == nil {
+		return fmt.Errorf("server error")
+	}
+	return fmt.Errorf("%s: %s", event.Error.Code, event.Error.Message)
+}
@@ -0,0 +1,264 @@
+package conversation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/client"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Strategy 上下文超限后的处理策略
+type Strategy int
+
+const (
+	// StrategyPrune 直接删除最早的条目
+	StrategyPrune Strategy = iota
+	// StrategySummarize 将最早的条目总结为一条 system 消息后再删除
+	StrategySummarize
+)
+
+// ContextPolicy 上下文管理策略
+type ContextPolicy struct {
+	// MaxInputTokens response.done 中的 input_tokens 达到该值时触发
+	MaxInputTokens int64
+	// KeepRecent 保留最近的条目数
+	KeepRecent int
+	Strategy   Strategy
+	// Summarizer StrategySummarize 时使用，为空时退化为 StrategyPrune
+	Summarizer Summarizer
+	// Timeout 单次总结的超时时间，默认 60 秒
+	Timeout time.Duration
+}
+
+// Summarizer 将一组对话条目总结为一段文本
+type Summarizer interface {
+	Summarize(ctx context.Context, items []events.Item) (string, error)
+}
+
+const defaultSummaryPrompt = "请将以下对话历史总结为简洁的要点，保留用户的需求、关键事实以及已经得出的结论，供后续对话参考。"
+
+// ChatSummarizer 通过 chat completions 接口总结对话
+type ChatSummarizer struct {
+	APIKey string
+	// Model 默认为 glm-4-flash
+	Model string
+	// Prompt 总结提示词，为空时使用默认提示词
+	Prompt string
+}
+
+func (s *ChatSummarizer) Summarize(ctx context.Context, items []events.Item) (string, error) {
+	model, prompt := s.Model, s.Prompt
+	if model == "" {
+		model = "glm-4-flash"
+	}
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+	return client.ChatCompletion(ctx, s.APIKey, model, []client.ChatMessage{
+		{Role: string(events.ItemRoleSystem), Content: prompt},
+		{Role: string(events.ItemRoleUser), Content: RenderItems(items)},
+	})
+}
+
+// RenderItems 将对话条目渲染为纯文本，每行一条
+func RenderItems(items []events.Item) string {
+	var b strings.Builder
+	for _, item := range items {
+		switch item.Type {
+		case events.ItemTypeMessage:
+			if text := contentText(item.Content); text != "" {
+				fmt.Fprintf(&b, "%s: %s\n", item.Role, text)
+			}
+		case events.ItemTypeFunctionCall:
+			fmt.Fprintf(&b, "function_call %s(%s)\n", item.Name, item.Arguments)
+		case events.ItemTypeFunctionCallOutput:
+			if item.Output != nil {
+				fmt.Fprintf(&b, "function_call_output: %s\n", *item.Output)
+			}
+		}
+	}
+	return b.String()
+}
+
+// ContextManager 监听 response.done 中的用量，上下文超限时按策略裁剪或总结最早的对话条目
+type ContextManager struct {
+	sender  events.Sender
+	tracker *Tracker
+	policy  ContextPolicy
+
+	mu      sync.Mutex
+	running bool
+	pending map[string]bool
+}
+
+// NewContextManager 创建上下文管理器，tracker 需要和 manager 观察同一个事件流
+func NewContextManager(sender events.Sender, tracker *Tracker, policy ContextPolicy) *ContextManager {
+	if policy.Timeout <= 0 {
+		policy.Timeout = 60 * time.Second
+	}
+	return &ContextManager{
+		sender:  sender,
+		tracker: tracker,
+		policy:  policy,
+		pending: make(map[string]bool),
+	}
+}
+
+// Observe 处理一个服务端事件，总结在后台进行，不会阻塞事件回调
+func (m *ContextManager) Observe(event *events.Event) {
+	if event == nil {
+		return
+	}
+	switch event.Type {
+	case events.RealtimeServerEventConversationItemDeleted:
+		m.mu.Lock()
+		delete(m.pending, event.ItemID)
+		m.mu.Unlock()
+	case events.RealtimeServerEventResponseDone:
+		if event.Response == nil || event.Response.Usage == nil || m.policy.MaxInputTokens <= 0 {
+			return
+		}
+		if event.Response.Usage.InputTokens < m.policy.MaxInputTokens {
+			return
+		}
+		m.mu.Lock()
+		if m.running {
+			m.mu.Unlock()
+			return
+		}
+		items := m.selectLocked()
+		if len(items) == 0 {
+			m.mu.Unlock()
+			return
+		}
+		m.running = true
+		m.mu.Unlock()
+
+		log.Printf("[ContextManager] Input tokens %d reached limit %d, compacting %d items\n", event.Response.Usage.InputTokens, m.policy.MaxInputTokens, len(items))
+		if m.policy.Strategy == StrategySummarize && m.policy.Summarizer != nil {
+			go m.summarize(items)
+		} else {
+			go m.prune(items)
+		}
+	}
+}
+
+// Compact 立即按策略处理一次，返回被移除的条目数
+func (m *ContextManager) Compact(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return 0, fmt.Errorf("compaction already running")
+	}
+	items := m.selectLocked()
+	m.running = len(items) > 0
+	m.mu.Unlock()
+	if len(items) == 0 {
+		return 0, nil
+	}
+	defer m.finish()
+
+	summary := ""
+	if m.policy.Strategy == StrategySummarize && m.policy.Summarizer != nil {
+		var err error
+		if summary, err = m.policy.Summarizer.Summarize(ctx, items); err != nil {
+			m.release(items)
+			return 0, err
+		}
+	}
+	return len(items), m.apply(items, summary)
+}
+
+// selectLocked 选出需要移除的最早条目，函数调用与其结果不会被拆开
+func (m *ContextManager) selectLocked() []events.Item {
+	all := m.tracker.Snapshot().Items
+	cut := len(all) - m.policy.KeepRecent
+	if cut <= 0 {
+		return nil
+	}
+	for cut < len(all) && all[cut].Type == events.ItemTypeFunctionCallOutput {
+		cut++
+	}
+	var items []events.Item
+	for _, item := range all[:cut] {
+		if item.ID == "" || m.pending[item.ID] {
+			continue
+		}
+		m.pending[item.ID] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+func (m *ContextManager) summarize(items []events.Item) {
+	defer m.finish()
+	ctx, cancel := context.WithTimeout(context.Background(), m.policy.Timeout)
+	defer cancel()
+	summary, err := m.policy.Summarizer.Summarize(ctx, items)
+	if err != nil {
+		log.Printf("[ContextManager] Summarize failed, err: %v\n", err)
+		m.release(items)
+		return
+	}
+	if err = m.apply(items, summary); err != nil {
+		log.Printf("[ContextManager] Apply summary failed, err: %v\n", err)
+	}
+}
+
+func (m *ContextManager) prune(items []events.Item) {
+	defer m.finish()
+	if err := m.apply(items, ""); err != nil {
+		log.Printf("[ContextManager] Prune failed, err: %v\n", err)
+	}
+}
+
+// apply 先在被移除条目之后插入总结，再删除这些条目，使总结正好位于保留的条目之前
+func (m *ContextManager) apply(items []events.Item, summary string) error {
+	if summary != "" {
+		text := summary
+		if err := m.sender.Send(&events.Event{
+			Type:           events.RealtimeClientEventConversationItemCreate,
+			PreviousItemID: items[len(items)-1].ID,
+			Item: &events.Item{
+				ID:      fmt.Sprintf("summary%d", time.Now().UnixNano()),
+				Object:  events.ItemObjectRealTimeItem,
+				Type:    events.ItemTypeMessage,
+				Status:  events.ItemStatusCompleted,
+				Role:    events.ItemRoleSystem,
+				Content: []events.Content{{Type: events.ContentTypeInputText, Text: &text}},
+			},
+		}); err != nil {
+			m.release(items)
+			return err
+		}
+	}
+	for i, item := range items {
+		if err := m.sender.Send(&events.Event{
+			Type:   events.RealtimeClientEventConversationItemDelete,
+			ItemID: item.ID,
+		}); err != nil {
+			m.release(items[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ContextManager) release(items []events.Item) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range items {
+		delete(m.pending, item.ID)
+	}
+}
+
+func (m *ContextManager) finish() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = false
+}
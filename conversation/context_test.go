@@ -0,0 +1,61 @@
+package conversation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+type recordingSender struct {
+	sent []*events.Event
+}
+
+func (s *recordingSender) Send(event *events.Event) error {
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+type staticSummarizer string
+
+func (s staticSummarizer) Summarize(ctx context.Context, items []events.Item) (string, error) {
+	return string(s), nil
+}
+
+func TestContextManagerCompact(t *testing.T) {
+	tracker := NewTracker()
+	for _, item := range []events.Item{
+		{ID: "u1", Type: events.ItemTypeMessage, Role: events.ItemRoleUser, Content: []events.Content{{Type: events.ContentTypeInputText, Text: strPtr("你好")}}},
+		{ID: "f1", Type: events.ItemTypeFunctionCall, Name: "SearchWeather", CallId: "call_1"},
+		{ID: "o1", Type: events.ItemTypeFunctionCallOutput, CallId: "call_1", Output: strPtr("晴")},
+		{ID: "a1", Type: events.ItemTypeMessage, Role: events.ItemRoleAssistant},
+	} {
+		item := item
+		tracker.Observe(&events.Event{Type: events.RealtimeServerEventConversationItemCreated, Item: &item})
+	}
+
+	sender := &recordingSender{}
+	manager := NewContextManager(sender, tracker, ContextPolicy{KeepRecent: 2, Strategy: StrategySummarize, Summarizer: staticSummarizer("用户问了北京天气")})
+	removed, err := manager.Compact(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 保留最近 2 条会拆开 f1/o1，因此 o1 也需要被移除
+	if removed != 3 || len(sender.sent) != 4 {
+		t.Fatalf("removed = %d, sent = %d", removed, len(sender.sent))
+	}
+	summary := sender.sent[0]
+	if summary.Type != events.RealtimeClientEventConversationItemCreate || summary.PreviousItemID != "o1" || summary.Item.Role != events.ItemRoleSystem {
+		t.Fatalf("unexpected summary event: %s", summary.ToJson())
+	}
+	for i, id := range []string{"u1", "f1", "o1"} {
+		if event := sender.sent[i+1]; event.Type != events.RealtimeClientEventConversationItemDelete || event.ItemID != id {
+			t.Fatalf("unexpected delete event: %s", event.ToJson())
+		}
+	}
+
+	// 删除尚未确认前不会重复删除
+	if removed, _ = manager.Compact(context.Background()); removed != 0 {
+		t.Fatalf("pending items should not be removed twice, removed = %d", removed)
+	}
+}
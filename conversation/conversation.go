@@ -0,0 +1,209 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// FormatVersion 序列化格式的版本号，格式不兼容变更时递增
+const FormatVersion = 1
+
+// Conversation 可移植的会话快照，包含会话配置（含工具定义）和按顺序排列的对话条目
+type Conversation struct {
+	Version   int             `json:"version"`
+	SessionID string          `json:"session_id,omitempty"`
+	Session   *events.Session `json:"session,omitempty"`
+	Items     []events.Item   `json:"items"`
+}
+
+// Save 将快照以 JSON 格式写入 w
+func (c *Conversation) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(c)
+}
+
+// Load 从 r 中读取 Save 写入的快照
+func Load(r io.Reader) (*Conversation, error) {
+	c := &Conversation{}
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+	if c.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported conversation format version: %d", c.Version)
+	}
+	return c, nil
+}
+
+// Tracker 监听服务端事件，维护当前会话的配置和对话条目
+type Tracker struct {
+	mu        sync.Mutex
+	sessionID string
+	session   *events.Session
+	items     []*events.Item
+	index     map[string]*events.Item
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{index: make(map[string]*events.Item)}
+}
+
+// Observe 处理一个服务端事件
+func (t *Tracker) Observe(event *events.Event) {
+	if event == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch event.Type {
+	case events.RealtimeServerEventSessionCreated, events.RealtimeServerEventSessionUpdated:
+		if event.Session != nil {
+			session := *event.Session
+			t.sessionID, t.session = session.ID, &session
+		}
+	case events.RealtimeServerEventConversationItemCreated:
+		if event.Item != nil {
+			t.insertLocked(event.Item, event.PreviousItemID)
+		}
+	case events.RealtimeServerEventResponseOutputItemAdded:
+		if event.Item != nil && t.index[event.Item.ID] == nil {
+			t.insertLocked(event.Item, t.lastIDLocked())
+		}
+	case events.RealtimeServerEventResponseOutputItemDone, events.RealtimeServerEventConversationItemRetrieved:
+		if event.Item == nil {
+			return
+		}
+		if item, ok := t.index[event.Item.ID]; ok {
+			*item = cloneItem(event.Item)
+		} else {
+			t.insertLocked(event.Item, t.lastIDLocked())
+		}
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted:
+		item, ok := t.index[event.ItemID]
+		if !ok || event.Transcript == nil {
+			return
+		}
+		if event.ContentIndex < len(item.Content) {
+			transcript := *event.Transcript
+			item.Content[event.ContentIndex].Transcript = &transcript
+		}
+	case events.RealtimeServerEventConversationItemDeleted:
+		t.deleteLocked(event.ItemID)
+	}
+}
+
+// Snapshot 返回当前会话的快照
+func (t *Tracker) Snapshot() *Conversation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := &Conversation{Version: FormatVersion, SessionID: t.sessionID, Items: make([]events.Item, 0, len(t.items))}
+	if t.session != nil {
+		session := *t.session
+		c.Session = &session
+	}
+	for _, item := range t.items {
+		c.Items = append(c.Items, cloneItem(item))
+	}
+	return c
+}
+
+func (t *Tracker) insertLocked(item *events.Item, previousItemID string) {
+	copied := cloneItem(item)
+	if old, ok := t.index[copied.ID]; ok && copied.ID != "" {
+		*old = copied
+		return
+	}
+	pos := len(t.items)
+	if previousItemID != "" {
+		for i, it := range t.items {
+			if it.ID == previousItemID {
+				pos = i + 1
+				break
+			}
+		}
+	}
+	t.items = append(t.items, nil)
+	copy(t.items[pos+1:], t.items[pos:])
+	t.items[pos] = &copied
+	if copied.ID != "" {
+		t.index[copied.ID] = &copied
+	}
+}
+
+func (t *Tracker) deleteLocked(itemID string) {
+	if _, ok := t.index[itemID]; !ok {
+		return
+	}
+	delete(t.index, itemID)
+	for i, it := range t.items {
+		if it.ID == itemID {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *Tracker) lastIDLocked() string {
+	if len(t.items) == 0 {
+		return ""
+	}
+	return t.items[len(t.items)-1].ID
+}
+
+// cloneItem 深拷贝条目，Tracker 之后会原地修改 Content 中的转写文本，不能与调用方的事件或已返回的快照共享。
+// Extra 中的 json.RawMessage 不会被原地修改，只复制 map
+func cloneItem(item *events.Item) events.Item {
+	copied := *item
+	if item.Output != nil {
+		output := *item.Output
+		copied.Output = &output
+	}
+	copied.Extra = cloneExtra(item.Extra)
+	if item.Content != nil {
+		copied.Content = make([]events.Content, len(item.Content))
+		for i, c := range item.Content {
+			if c.Text != nil {
+				text := *c.Text
+				c.Text = &text
+			}
+			if c.Transcript != nil {
+				transcript := *c.Transcript
+				c.Transcript = &transcript
+			}
+			if c.Image != nil {
+				c.Image = append([]byte(nil), c.Image...)
+			}
+			c.Extra = cloneExtra(c.Extra)
+			copied.Content[i] = c
+		}
+	}
+	return copied
+}
+
+func cloneExtra(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	if extra == nil {
+		return nil
+	}
+	copied := make(map[string]json.RawMessage, len(extra))
+	for k, v := range extra {
+		copied[k] = v
+	}
+	return copied
+}
+
+func contentText(contents []events.Content) string {
+	var b strings.Builder
+	for _, c := range contents {
+		if c.Text != nil {
+			b.WriteString(*c.Text)
+		} else if c.Transcript != nil {
+			b.WriteString(*c.Transcript)
+		}
+	}
+	return b.String()
+}
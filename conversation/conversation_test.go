@@ -0,0 +1,103 @@
+package conversation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestTrackerSnapshotAndRestore(t *testing.T) {
+	tracker := NewTracker()
+	for _, event := range []*events.Event{
+		{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_1", Object: "realtime.session", Voice: "default", Tools: []events.Tool{{Type: "function", Name: "SearchWeather"}}}},
+		{Type: events.RealtimeServerEventConversationItemCreated, Item: &events.Item{ID: "u1", Type: events.ItemTypeMessage, Role: events.ItemRoleUser, Content: []events.Content{{Type: events.ContentTypeInputAudio}}}},
+		{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u1", Transcript: strPtr("北京天气怎么样")},
+		{Type: events.RealtimeServerEventResponseOutputItemDone, Item: &events.Item{ID: "f1", Type: events.ItemTypeFunctionCall, Name: "SearchWeather", CallId: "call_1", Arguments: `{"location":"北京"}`}},
+		{Type: events.RealtimeServerEventConversationItemCreated, PreviousItemID: "f1", Item: &events.Item{ID: "o1", Type: events.ItemTypeFunctionCallOutput, CallId: "call_1", Output: strPtr("晴")}},
+		{Type: events.RealtimeServerEventResponseOutputItemDone, Item: &events.Item{ID: "a1", Type: events.ItemTypeMessage, Role: events.ItemRoleAssistant, Content: []events.Content{{Type: events.ContentTypeAudio, Transcript: strPtr("北京今天晴")}}}},
+		{Type: events.RealtimeServerEventConversationItemCreated, Item: &events.Item{ID: "x1", Type: events.ItemTypeMessage, Role: events.ItemRoleUser}},
+		{Type: events.RealtimeServerEventConversationItemDeleted, ItemID: "x1"},
+	} {
+		tracker.Observe(event)
+	}
+
+	var buf bytes.Buffer
+	if err := tracker.Snapshot().Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	conv, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conv.SessionID != "sess_1" || len(conv.Items) != 4 {
+		t.Fatalf("unexpected snapshot: %+v", conv)
+	}
+
+	restored := RestoreEvents(conv)
+	if len(restored) != 5 {
+		t.Fatalf("expected 5 restore events, got %d", len(restored))
+	}
+	if update := restored[0]; update.Type != events.RealtimeClientEventSessionUpdate || update.Session.ID != "" || len(update.Session.Tools) != 1 {
+		t.Fatalf("unexpected session.update: %s", update.ToJson())
+	}
+	previous := ""
+	for i, id := range []string{"u1", "f1", "o1", "a1"} {
+		event := restored[i+1]
+		if event.Type != events.RealtimeClientEventConversationItemCreate || event.Item.ID != id || event.PreviousItemID != previous {
+			t.Fatalf("unexpected restore event %d: %s", i, event.ToJson())
+		}
+		previous = id
+	}
+	if user := restored[1].Item.Content[0]; user.Type != events.ContentTypeInputText || *user.Text != "北京天气怎么样" {
+		t.Fatalf("user audio should be restored as text, got %+v", user)
+	}
+	if assistant := restored[4].Item.Content[0]; assistant.Type != events.ContentTypeText || *assistant.Text != "北京今天晴" {
+		t.Fatalf("assistant audio should be restored as text, got %+v", assistant)
+	}
+}
+
+func TestRestoreImageContent(t *testing.T) {
+	question := "这是什么"
+	restored := RestoreEvents(&Conversation{Items: []events.Item{{
+		ID: "u1", Type: events.ItemTypeMessage, Role: events.ItemRoleUser,
+		Content: []events.Content{
+			{Type: events.ContentTypeInputImage, ImageURL: "https://example.com/cat.png"},
+			{Type: events.ContentTypeInputImage, Image: []byte{0x89, 'P', 'N', 'G'}},
+			{Type: events.ContentTypeInputAudio},
+			{Type: events.ContentTypeInputText, Text: &question},
+		},
+	}}})
+	if len(restored) != 1 {
+		t.Fatalf("expected 1 restore event, got %d", len(restored))
+	}
+	content := restored[0].Item.Content
+	if len(content) != 3 || content[0].ImageURL != "https://example.com/cat.png" || string(content[1].Image) != "\x89PNG" || *content[2].Text != question {
+		t.Fatalf("images should be restored as-is, got %s", restored[0].ToJson())
+	}
+}
+
+func TestTrackerSnapshotIsolation(t *testing.T) {
+	tracker := NewTracker()
+	created := &events.Event{Type: events.RealtimeServerEventConversationItemCreated, Item: &events.Item{ID: "u1", Type: events.ItemTypeMessage, Role: events.ItemRoleUser, Content: []events.Content{{Type: events.ContentTypeInputAudio}}}}
+	tracker.Observe(created)
+	before := tracker.Snapshot()
+
+	tracker.Observe(&events.Event{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u1", Transcript: strPtr("你好")})
+	if transcript := before.Items[0].Content[0].Transcript; transcript != nil {
+		t.Fatalf("earlier snapshot changed: %q", *transcript)
+	}
+	if transcript := created.Item.Content[0].Transcript; transcript != nil {
+		t.Fatalf("caller's event changed: %q", *transcript)
+	}
+
+	after := tracker.Snapshot()
+	*after.Items[0].Content[0].Transcript = "改动"
+	if got := *tracker.Snapshot().Items[0].Content[0].Transcript; got != "你好" {
+		t.Fatalf("tracker state changed through snapshot: %q", got)
+	}
+}
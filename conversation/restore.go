@@ -0,0 +1,104 @@
+package conversation
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/client"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Restore 在新会话中恢复快照：建立连接，通过 session.update 重新应用会话配置，
+// 再以 previous_item_id 串联的 conversation.item.create 依次重建对话历史
+func Restore(c client.RealtimeClient, conv *Conversation) error {
+	if err := c.Connect(); err != nil {
+		return err
+	}
+	for _, event := range RestoreEvents(conv) {
+		if err := c.Send(event); err != nil {
+			return fmt.Errorf("restore %s failed: %w", event.Type, err)
+		}
+	}
+	return nil
+}
+
+// RestoreEvents 生成恢复快照所需的客户端事件，不包含无法重放的条目和内容（例如没有转写文本的音频），
+// 被跳过的条目和内容会记录日志
+func RestoreEvents(conv *Conversation) []*events.Event {
+	var result []*events.Event
+	if conv == nil {
+		return result
+	}
+	if conv.Session != nil {
+		session := *conv.Session
+		session.ID, session.Object = "", ""
+		result = append(result, &events.Event{
+			Type:    events.RealtimeClientEventSessionUpdate,
+			Session: &session,
+		})
+	}
+	previousItemID := ""
+	for _, item := range conv.Items {
+		restored, ok := restorableItem(item)
+		if !ok {
+			log.Printf("[Conversation] Skipping item %s of type %s, err: nothing to restore\n", item.ID, item.Type)
+			continue
+		}
+		result = append(result, &events.Event{
+			Type:           events.RealtimeClientEventConversationItemCreate,
+			PreviousItemID: previousItemID,
+			Item:           &restored,
+		})
+		if restored.ID != "" {
+			previousItemID = restored.ID
+		}
+	}
+	return result
+}
+
+// restorableItem 将服务端返回的条目转换为可以通过 conversation.item.create 重新创建的形式，
+// 音频内容无法重放，使用其转写文本代替；图片内容原样保留
+func restorableItem(item events.Item) (events.Item, bool) {
+	restored := events.Item{
+		ID:     item.ID,
+		Object: events.ItemObjectRealTimeItem,
+		Type:   item.Type,
+		Status: events.ItemStatusCompleted,
+	}
+	switch item.Type {
+	case events.ItemTypeMessage:
+		restored.Role = item.Role
+		for _, c := range item.Content {
+			if c.Type == events.ContentTypeInputImage && (c.ImageURL != "" || len(c.Image) > 0) {
+				restored.Content = append(restored.Content, events.Content{
+					Type:     c.Type,
+					ImageURL: c.ImageURL,
+					Image:    append([]byte(nil), c.Image...),
+				})
+				continue
+			}
+			text := c.Text
+			if text == nil {
+				text = c.Transcript
+			}
+			if text == nil || *text == "" {
+				log.Printf("[Conversation] Dropping %s content of item %s, err: no text or transcript to restore\n", c.Type, item.ID)
+				continue
+			}
+			contentType := events.ContentTypeInputText
+			if item.Role == events.ItemRoleAssistant {
+				contentType = events.ContentTypeText
+			}
+			value := *text
+			restored.Content = append(restored.Content, events.Content{Type: contentType, Text: &value})
+		}
+		return restored, len(restored.Content) > 0
+	case events.ItemTypeFunctionCall:
+		restored.Name, restored.CallId, restored.Arguments = item.Name, item.CallId, item.Arguments
+		return restored, item.CallId != ""
+	case events.ItemTypeFunctionCallOutput:
+		restored.CallId, restored.Output = item.CallId, item.Output
+		return restored, item.CallId != "" && item.Output != nil
+	}
+	return restored, false
+}
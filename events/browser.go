@@ -0,0 +1,259 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SearchSource 联网搜索返回的一条来源
+type SearchSource struct {
+	Index       int    `json:"index,omitempty"`
+	Refer       string `json:"refer,omitempty"`
+	Title       string `json:"title,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+	Site        string `json:"site,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	PublishDate string `json:"publish_date,omitempty"`
+}
+
+// SearchResult simple_browser 的一次搜索结果
+type SearchResult struct {
+	Query   string         `json:"query,omitempty"`
+	Sources []SearchSource `json:"sources,omitempty"`
+}
+
+// Citation 回答文本中引用的来源
+type Citation struct {
+	Index int    `json:"index,omitempty"`
+	Refer string `json:"refer,omitempty"`
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url,omitempty"`
+	// Text 回答中引用该来源的文本片段
+	Text string `json:"text,omitempty"`
+}
+
+// BrowserSearch simple_browser 事件解析后的搜索信息
+type BrowserSearch struct {
+	ResponseID  string        `json:"response_id,omitempty"`
+	ItemID      string        `json:"item_id,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Meta        string        `json:"meta,omitempty"`
+	Result      *SearchResult `json:"result,omitempty"`
+	Citations   []Citation    `json:"citations,omitempty"`
+}
+
+// SimpleBrowserFromEvent 取出 simple_browser 事件携带的搜索信息，不存在时返回 nil
+func SimpleBrowserFromEvent(e *Event) *SimpleBrowser {
+	if e == nil {
+		return nil
+	}
+	if e.BetaFields != nil && e.BetaFields.SimpleBrowser != nil {
+		return e.BetaFields.SimpleBrowser
+	}
+	if e.Session != nil && e.Session.BetaFields != nil {
+		return e.Session.BetaFields.SimpleBrowser
+	}
+	return nil
+}
+
+// ParseSimpleBrowser 解析 response.function_call.simple_browser 和 .simple_browser.result 事件，
+// 事件不携带搜索信息时返回 nil
+func ParseSimpleBrowser(e *Event) (*BrowserSearch, error) {
+	browser := SimpleBrowserFromEvent(e)
+	if browser == nil {
+		return nil, nil
+	}
+	result, err := browser.ParseSearchMeta()
+	if err != nil {
+		return nil, err
+	}
+	citations, err := browser.ParseCitations(result.Sources)
+	if err != nil {
+		return nil, err
+	}
+	return &BrowserSearch{
+		ResponseID:  e.ResponseID,
+		ItemID:      e.ItemID,
+		Description: browser.Description,
+		Meta:        browser.Meta,
+		Result:      result,
+		Citations:   citations,
+	}, nil
+}
+
+// rawSource 兼容搜索结果中不同的字段命名
+type rawSource struct {
+	Index       json.RawMessage `json:"index"`
+	ID          json.RawMessage `json:"id"`
+	Refer       string          `json:"refer"`
+	Title       string          `json:"title"`
+	URL         string          `json:"url"`
+	Link        string          `json:"link"`
+	Snippet     string          `json:"snippet"`
+	Content     string          `json:"content"`
+	Summary     string          `json:"summary"`
+	Text        string          `json:"text"`
+	Media       string          `json:"media"`
+	Site        string          `json:"site"`
+	Icon        string          `json:"icon"`
+	PublishDate string          `json:"publish_date"`
+}
+
+func (r *rawSource) source(position int) SearchSource {
+	s := SearchSource{
+		Index:       rawIndex(r.Index, r.ID, r.Refer),
+		Refer:       r.Refer,
+		Title:       r.Title,
+		URL:         firstNonEmpty(r.URL, r.Link),
+		Snippet:     firstNonEmpty(r.Snippet, r.Content, r.Summary),
+		Site:        firstNonEmpty(r.Site, r.Media),
+		Icon:        r.Icon,
+		PublishDate: r.PublishDate,
+	}
+	if s.Index == 0 {
+		s.Index = position + 1
+	}
+	return s
+}
+
+// ParseSearchMeta 解析 search_meta，支持来源数组或带有 query 和结果数组的对象两种形式
+func (s *SimpleBrowser) ParseSearchMeta() (*SearchResult, error) {
+	result := &SearchResult{}
+	data := strings.TrimSpace(s.SearchMeta)
+	if data == "" {
+		return result, nil
+	}
+	var sources []rawSource
+	if strings.HasPrefix(data, "[") {
+		if err := json.Unmarshal([]byte(data), &sources); err != nil {
+			return nil, fmt.Errorf("parse search_meta failed: %w", err)
+		}
+	} else {
+		var meta struct {
+			Query        string      `json:"query"`
+			SearchQuery  string      `json:"search_query"`
+			Results      []rawSource `json:"results"`
+			SearchResult []rawSource `json:"search_result"`
+			Sources      []rawSource `json:"sources"`
+		}
+		if err := json.Unmarshal([]byte(data), &meta); err != nil {
+			return nil, fmt.Errorf("parse search_meta failed: %w", err)
+		}
+		result.Query = firstNonEmpty(meta.Query, meta.SearchQuery)
+		sources = append(append(append(sources, meta.Results...), meta.SearchResult...), meta.Sources...)
+	}
+	for i := range sources {
+		result.Sources = append(result.Sources, sources[i].source(i))
+	}
+	if result.Query == "" {
+		result.Query = s.Description
+	}
+	return result, nil
+}
+
+var citationMarker = regexp.MustCompile(`【(\d+)(?:†[^】]*)?】|\[(?:ref_)?(\d+)\]`)
+
+// ParseCitations 解析 text_citation。JSON 形式直接解析；纯文本形式按 【1】、【1†source】、[1]、[ref_1]
+// 等标记提取引用编号，并从 sources 中补全标题和链接
+func (s *SimpleBrowser) ParseCitations(sources []SearchSource) ([]Citation, error) {
+	data := strings.TrimSpace(s.TextCitation)
+	if data == "" {
+		return nil, nil
+	}
+	var citations []Citation
+	if strings.HasPrefix(data, "[{") || strings.HasPrefix(data, "{") {
+		var raw []rawSource
+		if strings.HasPrefix(data, "{") {
+			var wrapper struct {
+				Citations []rawSource `json:"citations"`
+			}
+			if err := json.Unmarshal([]byte(data), &wrapper); err != nil {
+				return nil, fmt.Errorf("parse text_citation failed: %w", err)
+			}
+			raw = wrapper.Citations
+		} else if err := json.Unmarshal([]byte(data), &raw); err != nil {
+			return nil, fmt.Errorf("parse text_citation failed: %w", err)
+		}
+		for i := range raw {
+			source := raw[i].source(i)
+			citations = append(citations, Citation{
+				Index: source.Index,
+				Refer: source.Refer,
+				Title: source.Title,
+				URL:   source.URL,
+				Text:  firstNonEmpty(raw[i].Text, raw[i].Content),
+			})
+		}
+	} else {
+		seen := make(map[int]bool)
+		for _, m := range citationMarker.FindAllStringSubmatch(data, -1) {
+			index, _ := strconv.Atoi(firstNonEmpty(m[1], m[2]))
+			if index == 0 || seen[index] {
+				continue
+			}
+			seen[index] = true
+			citations = append(citations, Citation{Index: index})
+		}
+	}
+	for i := range citations {
+		for _, source := range sources {
+			if source.Index == citations[i].Index || (citations[i].Refer != "" && source.Refer == citations[i].Refer) {
+				citations[i].Title = firstNonEmpty(citations[i].Title, source.Title)
+				citations[i].URL = firstNonEmpty(citations[i].URL, source.URL)
+				break
+			}
+		}
+	}
+	return citations, nil
+}
+
+func rawIndex(values ...any) int {
+	for _, v := range values {
+		switch raw := v.(type) {
+		case json.RawMessage:
+			if len(raw) == 0 {
+				continue
+			}
+			var n int
+			if err := json.Unmarshal(raw, &n); err == nil && n > 0 {
+				return n
+			}
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil {
+				if n = trailingNumber(s); n > 0 {
+					return n
+				}
+			}
+		case string:
+			if n := trailingNumber(raw); n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+var trailingDigits = regexp.MustCompile(`(\d+)$`)
+
+// trailingNumber 取出 ref_1 这类标识末尾的编号
+func trailingNumber(s string) int {
+	m := trailingDigits.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
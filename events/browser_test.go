@@ -0,0 +1,32 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestParseCitationsJSON(t *testing.T) {
+	browser := &SimpleBrowser{
+		SearchMeta:   `[{"index":1,"title":"A","url":"https://a.example.com"}]`,
+		TextCitation: `[{"index":1,"text":"引用片段"},{"refer":"ref_3","title":"C","url":"https://c.example.com"}]`,
+	}
+	result, err := browser.ParseSearchMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	citations, err := browser.ParseCitations(result.Sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", citations)
+	}
+	if citations[0].Title != "A" || citations[0].Text != "引用片段" {
+		t.Errorf("unexpected citation: %+v", citations[0])
+	}
+	if citations[1].Index != 3 || citations[1].URL != "https://c.example.com" {
+		t.Errorf("unexpected citation: %+v", citations[1])
+	}
+	if _, err := (&SimpleBrowser{SearchMeta: "{bad"}).ParseSearchMeta(); err == nil {
+		t.Error("expected error for malformed search_meta")
+	}
+}
@@ -0,0 +1,192 @@
+package events
+
+var clientEventTypes = map[EventType]func() ClientEvent{
+	RealtimeClientEventSessionUpdate:              func() ClientEvent { return &SessionUpdateEvent{} },
+	RealtimeClientEventTranscriptionSessionUpdate: func() ClientEvent { return &TranscriptionSessionUpdateEvent{} },
+	RealtimeClientEventInputAudioBufferAppend:     func() ClientEvent { return &InputAudioBufferAppendEvent{} },
+	RealtimeClientVideoAppend:                     func() ClientEvent { return &InputAudioBufferAppendVideoFrameEvent{} },
+	RealtimeClientEventInputAudioBufferCommit:     func() ClientEvent { return &InputAudioBufferCommitEvent{} },
+	RealtimeClientEventInputAudioBufferClear:      func() ClientEvent { return &InputAudioBufferClearEvent{} },
+	RealtimeClientEventConversationItemCreate:     func() ClientEvent { return &ConversationItemCreateEvent{} },
+	RealtimeClientEventConversationItemRetrieve:   func() ClientEvent { return &ConversationItemRetrieveEvent{} },
+	RealtimeClientEventConversationItemTruncate:   func() ClientEvent { return &ConversationItemTruncateEvent{} },
+	RealtimeClientEventConversationItemDelete:     func() ClientEvent { return &ConversationItemDeleteEvent{} },
+	RealtimeClientEventResponseCreate:             func() ClientEvent { return &ResponseCreateEvent{} },
+	RealtimeClientEventResponseCancel:             func() ClientEvent { return &ResponseCancelEvent{} },
+}
+
+// SessionUpdateEvent session.update
+type SessionUpdateEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+func (SessionUpdateEvent) EventType() EventType { return RealtimeClientEventSessionUpdate }
+func (SessionUpdateEvent) clientEvent()         {}
+func (e SessionUpdateEvent) MarshalJSON() ([]byte, error) {
+	type alias SessionUpdateEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// TranscriptionSessionUpdateEvent transcription_session.update
+type TranscriptionSessionUpdateEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+func (TranscriptionSessionUpdateEvent) EventType() EventType {
+	return RealtimeClientEventTranscriptionSessionUpdate
+}
+func (TranscriptionSessionUpdateEvent) clientEvent() {}
+func (e TranscriptionSessionUpdateEvent) MarshalJSON() ([]byte, error) {
+	type alias TranscriptionSessionUpdateEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferAppendEvent input_audio_buffer.append，Audio 为原始音频数据，序列化时自动进行 base64 编码
+type InputAudioBufferAppendEvent struct {
+	EventID         string `json:"event_id,omitempty"`
+	Audio           []byte `json:"audio"`
+	ClientTimestamp int64  `json:"client_timestamp,omitempty"`
+}
+
+func (InputAudioBufferAppendEvent) EventType() EventType {
+	return RealtimeClientEventInputAudioBufferAppend
+}
+func (InputAudioBufferAppendEvent) clientEvent() {}
+func (e InputAudioBufferAppendEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferAppendEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferAppendVideoFrameEvent input_audio_buffer.append_video_frame，VideoFrame 为 JPEG 图片数据
+type InputAudioBufferAppendVideoFrameEvent struct {
+	EventID         string `json:"event_id,omitempty"`
+	VideoFrame      []byte `json:"video_frame"`
+	ClientTimestamp int64  `json:"client_timestamp,omitempty"`
+}
+
+func (InputAudioBufferAppendVideoFrameEvent) EventType() EventType { return RealtimeClientVideoAppend }
+func (InputAudioBufferAppendVideoFrameEvent) clientEvent()         {}
+func (e InputAudioBufferAppendVideoFrameEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferAppendVideoFrameEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferCommitEvent input_audio_buffer.commit
+type InputAudioBufferCommitEvent struct {
+	EventID         string `json:"event_id,omitempty"`
+	ClientTimestamp int64  `json:"client_timestamp,omitempty"`
+}
+
+func (InputAudioBufferCommitEvent) EventType() EventType {
+	return RealtimeClientEventInputAudioBufferCommit
+}
+func (InputAudioBufferCommitEvent) clientEvent() {}
+func (e InputAudioBufferCommitEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferCommitEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferClearEvent input_audio_buffer.clear
+type InputAudioBufferClearEvent struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+func (InputAudioBufferClearEvent) EventType() EventType {
+	return RealtimeClientEventInputAudioBufferClear
+}
+func (InputAudioBufferClearEvent) clientEvent() {}
+func (e InputAudioBufferClearEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferClearEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemCreateEvent conversation.item.create
+type ConversationItemCreateEvent struct {
+	EventID        string `json:"event_id,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	Item           Item   `json:"item"`
+}
+
+func (ConversationItemCreateEvent) EventType() EventType {
+	return RealtimeClientEventConversationItemCreate
+}
+func (ConversationItemCreateEvent) clientEvent() {}
+func (e ConversationItemCreateEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemCreateEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemRetrieveEvent conversation.item.retrieve
+type ConversationItemRetrieveEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	ItemID  string `json:"item_id"`
+}
+
+func (ConversationItemRetrieveEvent) EventType() EventType {
+	return RealtimeClientEventConversationItemRetrieve
+}
+func (ConversationItemRetrieveEvent) clientEvent() {}
+func (e ConversationItemRetrieveEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemRetrieveEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemTruncateEvent conversation.item.truncate
+type ConversationItemTruncateEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMS   int64  `json:"audio_end_ms"`
+}
+
+func (ConversationItemTruncateEvent) EventType() EventType {
+	return RealtimeClientEventConversationItemTruncate
+}
+func (ConversationItemTruncateEvent) clientEvent() {}
+func (e ConversationItemTruncateEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemTruncateEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemDeleteEvent conversation.item.delete
+type ConversationItemDeleteEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	ItemID  string `json:"item_id"`
+}
+
+func (ConversationItemDeleteEvent) EventType() EventType {
+	return RealtimeClientEventConversationItemDelete
+}
+func (ConversationItemDeleteEvent) clientEvent() {}
+func (e ConversationItemDeleteEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemDeleteEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseCreateEvent response.create，Response 为 nil 时使用会话配置
+type ResponseCreateEvent struct {
+	EventID  string    `json:"event_id,omitempty"`
+	Response *Response `json:"response,omitempty"`
+}
+
+func (ResponseCreateEvent) EventType() EventType { return RealtimeClientEventResponseCreate }
+func (ResponseCreateEvent) clientEvent()         {}
+func (e ResponseCreateEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseCreateEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseCancelEvent response.cancel，ResponseID 为空时取消当前响应
+type ResponseCancelEvent struct {
+	EventID    string `json:"event_id,omitempty"`
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+func (ResponseCancelEvent) EventType() EventType { return RealtimeClientEventResponseCancel }
+func (ResponseCancelEvent) clientEvent()         {}
+func (e ResponseCancelEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseCancelEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
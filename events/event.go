@@ -2,6 +2,7 @@ package events
 
 import (
 	"encoding/json"
+	"reflect"
 )
 
 type EventType string
@@ -28,6 +29,7 @@ const (
 	RealtimeServerEventConversationCreated                              EventType = "conversation.created"
 	RealtimeServerEventConversationItemCreated                          EventType = "conversation.item.created"
 	RealtimeServerEventConversationItemRetrieved                        EventType = "conversation.item.retrieved"
+	RealtimeServerEventConversationItemInputAudioTranscriptionDelta     EventType = "conversation.item.input_audio_transcription.delta"
 	RealtimeServerEventConversationItemInputAudioTranscriptionCompleted EventType = "conversation.item.input_audio_transcription.completed"
 	RealtimeServerEventConversationItemInputAudioTranscriptionFailed    EventType = "conversation.item.input_audio_transcription.failed"
 	RealtimeServerEventConversationItemTruncated                        EventType = "conversation.item.truncated"
@@ -57,6 +59,9 @@ const (
 	RealtimeClientInputVideoFrameAppend                        EventType = "input_audio_buffer.append_video_frame"
 	RealtimeServerResponseFunctionCallSimpleBrowserEvent       EventType = "response.function_call.simple_browser"
 	RealtimeServerResponseFunctionCallSimpleBrowserResultEvent EventType = "response.function_call.simple_browser.result"
+
+	// SDK 本地生成的事件，不会发送到服务端
+	RealtimeClientUsageBudgetExceededEvent EventType = "client.usage.budget_exceeded"
 )
 
 type Event struct {
@@ -86,7 +91,26 @@ type Event struct {
 	AudioStartMS    int64         `json:"audio_start_ms,omitempty"`
 	AudioEndMS      int64         `json:"audio_end_ms,omitempty"`
 	RateLimits      []RateLimit   `json:"rate_limits,omitempty"`
-	// BetaFields      *BetaFields `json:"beta_fields,omitempty"`
+	BetaFields      *BetaFields   `json:"beta_fields,omitempty"`
+	// Extra 未声明的字段，解析时保留，序列化时原样输出
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	type alias Event
+	return marshalWithExtra(alias(e), e.Extra)
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type alias Event
+	var a alias
+	extra, err := unmarshalWithExtra(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*e = Event(a)
+	e.Extra = extra
+	return nil
 }
 
 type EventError struct {
@@ -94,6 +118,8 @@ type EventError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Param   string `json:"param,omitempty"`
+	// EventID 引发错误的客户端事件的 event_id
+	EventID string `json:"event_id,omitempty"`
 }
 
 type Conversation struct {
@@ -140,39 +166,92 @@ const (
 )
 
 type Session struct {
-	ID                       string                   `json:"id,omitempty"`
-	Object                   string                   `json:"object,omitempty"`
-	Model                    string                   `json:"model,omitempty"`
-	Modalities               []Modality               `json:"modalities,omitempty"`
-	Instructions             string                   `json:"instructions,omitempty"`
-	Voice                    string                   `json:"voice,omitempty"`
-	InputAudioFormat         string                   `json:"input_audio_format,omitempty"`
-	OutputAudioFormat        string                   `json:"output_audio_format,omitempty"`
-	InputAudioTranscription  *InputAudioTranscription `json:"input_audio_transcription,omitempty"`
-	TurnDetection            *TurnDetection           `json:"turn_detection,omitempty"`
-	Tools                    []Tool                   `json:"tools,omitempty"`
-	ToolChoice               string                   `json:"tool_choice,omitempty"`
-	Temperature              float64                  `json:"temperature,omitempty"`
-	MaxResponseOutputTokens  any                      `json:"max_response_output_tokens,omitempty"` // "inf" or int
-	InputAudioNoiseReduction *NoiseReduction          `json:"input_audio_noise_reduction,omitempty"`
-	BetaFields               *BetaFields              `json:"beta_fields,omitempty"`
+	ID                       string                            `json:"id,omitempty"`
+	Object                   string                            `json:"object,omitempty"`
+	Model                    string                            `json:"model,omitempty"`
+	Modalities               []Modality                        `json:"modalities,omitempty"`
+	Instructions             string                            `json:"instructions,omitempty"`
+	Voice                    string                            `json:"voice,omitempty"`
+	InputAudioFormat         string                            `json:"input_audio_format,omitempty"`
+	OutputAudioFormat        string                            `json:"output_audio_format,omitempty"`
+	InputAudioTranscription  Optional[InputAudioTranscription] `json:"input_audio_transcription,omitempty"`
+	TurnDetection            Optional[TurnDetection]           `json:"turn_detection,omitempty"` // 客户端 VAD 模式需设置为 Null
+	Tools                    []Tool                            `json:"tools,omitempty"`
+	ToolChoice               string                            `json:"tool_choice,omitempty"`
+	Temperature              Optional[float64]                 `json:"temperature,omitempty"`
+	MaxResponseOutputTokens  any                               `json:"max_response_output_tokens,omitempty"` // "inf" or int
+	InputAudioNoiseReduction Optional[NoiseReduction]          `json:"input_audio_noise_reduction,omitempty"`
+	BetaFields               *BetaFields                       `json:"beta_fields,omitempty"`
 	// 这里是专门为了调式用的， 必须为指针，内部字段不暴露
 	FlowBackend *string `json:"flow_backend,omitempty"`
 	TTSBackend  *string `json:"tts_backend,omitempty"`
+	// Extra 未声明的字段，解析时保留，序列化时原样输出
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (s Session) MarshalJSON() ([]byte, error) {
+	type alias Session
+	// 同名字段层级更浅，覆盖 alias 中的 Optional 字段，未设置时省略
+	return marshalWithExtra(struct {
+		alias
+		InputAudioTranscription  *Optional[InputAudioTranscription] `json:"input_audio_transcription,omitempty"`
+		TurnDetection            *Optional[TurnDetection]           `json:"turn_detection,omitempty"`
+		Temperature              *Optional[float64]                 `json:"temperature,omitempty"`
+		InputAudioNoiseReduction *Optional[NoiseReduction]          `json:"input_audio_noise_reduction,omitempty"`
+	}{
+		alias:                    alias(s),
+		InputAudioTranscription:  s.InputAudioTranscription.omit(),
+		TurnDetection:            s.TurnDetection.omit(),
+		Temperature:              s.Temperature.omit(),
+		InputAudioNoiseReduction: s.InputAudioNoiseReduction.omit(),
+	}, s.Extra)
+}
+
+func (s *Session) UnmarshalJSON(data []byte) error {
+	type alias Session
+	var a alias
+	extra, err := unmarshalWithExtra(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*s = Session(a)
+	s.Extra = extra
+	return nil
 }
 
 type InputAudioTranscription struct {
-	Enabled bool   `json:"enabled"`
-	Model   string `json:"model"`
+	Enabled  bool   `json:"enabled"`
+	Model    string `json:"model"`
+	Language string `json:"language,omitempty"` // ISO-639-1 语言代码，如 zh
+	Prompt   string `json:"prompt,omitempty"`
 }
 
 type TurnDetection struct {
-	Type              string  `json:"type,omitempty"`
-	Threshold         float64 `json:"threshold,omitempty"`
-	PrefixPaddingMs   int     `json:"prefix_padding_ms,omitempty"`
-	SilenceDurationMs int     `json:"silence_duration_ms,omitempty"`
-	CreateResponse    bool    `json:"create_response,omitempty"`
-	InterruptResponse bool    `json:"interrupt_response,omitempty"`
+	Type              string            `json:"type,omitempty"`
+	Threshold         Optional[float64] `json:"threshold,omitempty"`
+	PrefixPaddingMs   Optional[int]     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs Optional[int]     `json:"silence_duration_ms,omitempty"`
+	CreateResponse    Optional[bool]    `json:"create_response,omitempty"`
+	InterruptResponse Optional[bool]    `json:"interrupt_response,omitempty"`
+}
+
+func (t TurnDetection) MarshalJSON() ([]byte, error) {
+	type alias TurnDetection
+	return json.Marshal(struct {
+		alias
+		Threshold         *Optional[float64] `json:"threshold,omitempty"`
+		PrefixPaddingMs   *Optional[int]     `json:"prefix_padding_ms,omitempty"`
+		SilenceDurationMs *Optional[int]     `json:"silence_duration_ms,omitempty"`
+		CreateResponse    *Optional[bool]    `json:"create_response,omitempty"`
+		InterruptResponse *Optional[bool]    `json:"interrupt_response,omitempty"`
+	}{
+		alias:             alias(t),
+		Threshold:         t.Threshold.omit(),
+		PrefixPaddingMs:   t.PrefixPaddingMs.omit(),
+		SilenceDurationMs: t.SilenceDurationMs.omit(),
+		CreateResponse:    t.CreateResponse.omit(),
+		InterruptResponse: t.InterruptResponse.omit(),
+	})
 }
 
 type NoiseReduction struct {
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ExtensionEvent 通过 ExtensionRegistry 注册的自定义事件，Payload 为注册时指定类型的指针
+type ExtensionEvent struct {
+	Type    EventType
+	Payload any
+}
+
+func (e ExtensionEvent) EventType() EventType { return e.Type }
+func (ExtensionEvent) clientEvent()           {}
+func (ExtensionEvent) serverEvent()           {}
+
+func (e ExtensionEvent) MarshalJSON() ([]byte, error) {
+	return marshalTyped(e.Type, e.Payload)
+}
+
+type extension struct {
+	payloadType reflect.Type
+	handlers    []func(payload any)
+}
+
+// ExtensionRegistry 自定义事件注册表，将自定义事件类型解析为注册的负载类型并交给处理函数
+type ExtensionRegistry struct {
+	mu    sync.RWMutex
+	types map[EventType]*extension
+}
+
+// DefaultExtensions ParseServerEvent 和 ParseClientEvent 使用的默认注册表
+var DefaultExtensions = NewExtensionRegistry()
+
+func NewExtensionRegistry() *ExtensionRegistry {
+	return &ExtensionRegistry{types: make(map[EventType]*extension)}
+}
+
+// RegisterExtension 注册自定义事件类型 t，事件 JSON 会被解析为 *T，handler 可以为 nil。
+// 同一类型可以多次注册处理函数，但负载类型必须一致；内置事件类型不能注册
+func RegisterExtension[T any](r *ExtensionRegistry, t EventType, handler func(payload *T)) error {
+	if t == "" {
+		return fmt.Errorf("event type is empty")
+	}
+	if _, ok := serverEventTypes[t]; ok {
+		return fmt.Errorf("event type %s is built in", t)
+	}
+	if _, ok := clientEventTypes[t]; ok {
+		return fmt.Errorf("event type %s is built in", t)
+	}
+	payloadType := reflect.TypeOf((*T)(nil)).Elem()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ext, ok := r.types[t]
+	if !ok {
+		ext = &extension{payloadType: payloadType}
+		r.types[t] = ext
+	} else if ext.payloadType != payloadType {
+		return fmt.Errorf("event type %s is already registered with payload %s", t, ext.payloadType)
+	}
+	if handler != nil {
+		ext.handlers = append(ext.handlers, func(payload any) { handler(payload.(*T)) })
+	}
+	return nil
+}
+
+// Unregister 移除自定义事件类型及其处理函数
+func (r *ExtensionRegistry) Unregister(t EventType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.types, t)
+}
+
+// Has 判断事件类型是否已注册
+func (r *ExtensionRegistry) Has(t EventType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.types[t]
+	return ok
+}
+
+// Decode 将已注册类型的事件解析为 *ExtensionEvent，未注册的类型返回 nil
+func (r *ExtensionRegistry) Decode(data []byte) (*ExtensionEvent, error) {
+	typ, err := peekType(data)
+	if err != nil {
+		return nil, err
+	}
+	event, _, err := r.decode(typ, data)
+	return event, err
+}
+
+// Dispatch 解析已注册类型的事件并依次调用处理函数，返回事件是否已注册
+func (r *ExtensionRegistry) Dispatch(data []byte) (bool, error) {
+	typ, err := peekType(data)
+	if err != nil {
+		return false, err
+	}
+	event, handlers, err := r.decode(typ, data)
+	if err != nil || event == nil {
+		return event != nil, err
+	}
+	for _, handler := range handlers {
+		handler(event.Payload)
+	}
+	return true, nil
+}
+
+// DispatchEvent 与 Dispatch 相同，用于已解析为 Event 的事件，Event.Extra 中的字段同样会被解析
+func (r *ExtensionRegistry) DispatchEvent(event *Event) (bool, error) {
+	if event == nil || !r.Has(event.Type) {
+		return false, nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return true, err
+	}
+	return r.Dispatch(data)
+}
+
+func (r *ExtensionRegistry) decode(typ EventType, data []byte) (*ExtensionEvent, []func(payload any), error) {
+	r.mu.RLock()
+	ext, ok := r.types[typ]
+	var handlers []func(payload any)
+	if ok {
+		handlers = append(handlers, ext.handlers...)
+	}
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil, nil
+	}
+	payload := reflect.New(ext.payloadType).Interface()
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, nil, fmt.Errorf("parse %s event failed: %w", typ, err)
+	}
+	return &ExtensionEvent{Type: typ, Payload: payload}, handlers, nil
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type gatewayQuota struct {
+	Remaining int    `json:"remaining"`
+	Plan      string `json:"plan"`
+}
+
+func TestExtensionRegistry(t *testing.T) {
+	r := NewExtensionRegistry()
+	var received []*gatewayQuota
+	if err := RegisterExtension(r, "gateway.quota", func(payload *gatewayQuota) {
+		received = append(received, payload)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterExtension[gatewayQuota](r, RealtimeServerEventResponseDone, nil); err == nil {
+		t.Error("expected error for built-in event type")
+	}
+	if err := RegisterExtension[struct{}](r, "gateway.quota", nil); err == nil {
+		t.Error("expected error for mismatched payload type")
+	}
+
+	data := []byte(`{"type":"gateway.quota","remaining":3,"plan":"pro"}`)
+	handled, err := r.Dispatch(data)
+	if err != nil || !handled {
+		t.Fatalf("Dispatch = %v, %v", handled, err)
+	}
+	if len(received) != 1 || received[0].Remaining != 3 || received[0].Plan != "pro" {
+		t.Fatalf("unexpected payload: %+v", received)
+	}
+	if handled, _ := r.Dispatch([]byte(`{"type":"response.done"}`)); handled {
+		t.Error("unregistered event should not be handled")
+	}
+
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatal(err)
+	}
+	if handled, err := r.DispatchEvent(&event); !handled || err != nil || len(received) != 2 || received[1].Remaining != 3 {
+		t.Fatalf("DispatchEvent = %v, %v, %+v", handled, err, received)
+	}
+
+	decoded, err := r.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != string(data) {
+		t.Errorf("marshal = %s, want %s", out, data)
+	}
+}
+
+func TestParseServerEventUsesDefaultExtensions(t *testing.T) {
+	if err := RegisterExtension[gatewayQuota](DefaultExtensions, "gateway.quota", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer DefaultExtensions.Unregister("gateway.quota")
+
+	event, err := ParseServerEvent([]byte(`{"type":"gateway.quota","remaining":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext, ok := event.(*ExtensionEvent)
+	if !ok {
+		t.Fatalf("unexpected event type %T", event)
+	}
+	if quota, ok := ext.Payload.(*gatewayQuota); !ok || quota.Remaining != 1 {
+		t.Errorf("unexpected payload: %#v", ext.Payload)
+	}
+	if _, err := ParseServerEvent([]byte(`{"type":"gateway.quota","remaining":"x"}`)); err == nil {
+		t.Error("expected error for malformed payload")
+	}
+}
@@ -0,0 +1,226 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var jsonKeysCache sync.Map // reflect.Type -> map[string]bool
+
+// jsonKeys 返回结构体通过 json 标签声明的所有字段名
+func jsonKeys(t reflect.Type) map[string]bool {
+	if keys, ok := jsonKeysCache.Load(t); ok {
+		return keys.(map[string]bool)
+	}
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		keys[name] = true
+	}
+	jsonKeysCache.Store(t, keys)
+	return keys
+}
+
+// splitExtra 将 JSON 对象拆分为结构体已声明的字段和未声明的字段
+func splitExtra(data []byte, t reflect.Type) (known, extra map[string]json.RawMessage, err error) {
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, err
+	}
+	keys := jsonKeys(t)
+	known = make(map[string]json.RawMessage, len(fields))
+	for k, v := range fields {
+		if keys[k] {
+			known[k] = v
+		} else {
+			if extra == nil {
+				extra = make(map[string]json.RawMessage)
+			}
+			extra[k] = v
+		}
+	}
+	return known, extra, nil
+}
+
+// unmarshalWithExtra 将 data 解析到 v，并返回 t 未声明的字段。
+// 解析后只扫描一遍顶层字段名，跳过字段值而不解码，全部字段都已声明时不分配内存
+func unmarshalWithExtra(data []byte, t reflect.Type, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	keys := jsonKeys(t)
+	var extra map[string]json.RawMessage
+	err := scanObject(data, func(key, value []byte) error {
+		if keys[string(key)] {
+			return nil
+		}
+		name, err := unquoteKey(key)
+		if err != nil {
+			return err
+		}
+		if keys[name] {
+			return nil
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		// data 在返回后可能被复用，需要复制
+		extra[name] = append(json.RawMessage(nil), value...)
+		return nil
+	})
+	return extra, err
+}
+
+// scanObject 依次对 JSON 对象的每个顶层字段调用 fn，key 为未反转义的字段名，value 为原始 JSON。
+// data 需已通过 json.Unmarshal 校验
+func scanObject(data []byte, fn func(key, value []byte) error) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return fmt.Errorf("expected JSON object")
+	}
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != '}' {
+		end := skipString(data, i)
+		if end < 0 {
+			return fmt.Errorf("invalid JSON object key at offset %d", i)
+		}
+		key := data[i+1 : end-1]
+		i = skipSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return fmt.Errorf("expected ':' at offset %d", i)
+		}
+		start := skipSpace(data, i+1)
+		i = skipValue(data, start)
+		if i < 0 {
+			return fmt.Errorf("invalid JSON value at offset %d", start)
+		}
+		if err := fn(key, data[start:i]); err != nil {
+			return err
+		}
+		i = skipSpace(data, i)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return nil
+}
+
+// unquoteKey 处理字段名中的转义字符
+func unquoteKey(key []byte) (string, error) {
+	if bytes.IndexByte(key, '\\') < 0 {
+		return string(key), nil
+	}
+	var name string
+	err := json.Unmarshal(append(append([]byte{'"'}, key...), '"'), &name)
+	return name, err
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipString 返回从 data[i] 开始的字符串之后的位置，无效时返回 -1
+func skipString(data []byte, i int) int {
+	if i >= len(data) || data[i] != '"' {
+		return -1
+	}
+	for j := i + 1; ; {
+		n := bytes.IndexByte(data[j:], '"')
+		if n < 0 {
+			return -1
+		}
+		j += n
+		// 前面有奇数个反斜杠时引号被转义
+		escapes := 0
+		for k := j - 1; k > i && data[k] == '\\'; k-- {
+			escapes++
+		}
+		j++
+		if escapes%2 == 0 {
+			return j
+		}
+	}
+}
+
+// skipValue 返回从 data[i] 开始的 JSON 值之后的位置，无效时返回 -1
+func skipValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				if i = skipString(data, i); i < 0 {
+					return -1
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return -1
+	default:
+		// 数字、true、false、null
+		for i < len(data) && !bytes.ContainsRune([]byte(",}] \t\n\r"), rune(data[i])) {
+			i++
+		}
+		return i
+	}
+}
+
+// marshalWithExtra 序列化 v 并合并未声明的字段，已声明的字段优先
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k, raw := range extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = raw
+		}
+	}
+	return json.Marshal(fields)
+}
+
+func isJSONString(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '"'
+}
+
+// DecodeExtra 将未声明字段 key 解析到 v，字段不存在时返回 false
+func DecodeExtra(extra map[string]json.RawMessage, key string, v any) (bool, error) {
+	raw, ok := extra[key]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestEventPreservesUnknownFields(t *testing.T) {
+	data := `{"delta":"","event_id":"e1","new_field":{"a":1},"response":{"id":"r1","output":[{"call_id":"c1","content":[{"lang":"zh","type":"text"}],"id":"i1","object":"realtime.item","status":"completed","type":"message","x_item":true}],"x_response":"v"},"session":{"model":"glm-realtime","x_session":[1,2]},"type":"response.future_event"}`
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != "response.future_event" || event.Response.ID != "r1" {
+		t.Fatalf("known fields not parsed: %+v", event)
+	}
+	var field struct {
+		A int `json:"a"`
+	}
+	if ok, err := DecodeExtra(event.Extra, "new_field", &field); !ok || err != nil || field.A != 1 {
+		t.Errorf("DecodeExtra = %v, %v, %+v", ok, err, field)
+	}
+	if ok, _ := DecodeExtra(event.Extra, "missing", &field); ok {
+		t.Error("missing field reported as present")
+	}
+	if string(event.Response.Output[0].Content[0].Extra["lang"]) != `"zh"` {
+		t.Errorf("content extra not kept: %v", event.Response.Output[0].Content[0].Extra)
+	}
+
+	out, err := json.Marshal(&event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != data {
+		t.Errorf("round trip mismatch:\n got %s\nwant %s", out, data)
+	}
+}
+
+func TestUnknownFieldsEdgeCases(t *testing.T) {
+	data := `{ "type" : "x", "text":"t", "a\"b" : [ "}", {"c":"]"} ], "n":-1.5e3 ,"ok":true,"nil":null}`
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != "x" || event.Text == nil || *event.Text != "t" {
+		t.Fatalf("known fields not parsed: %+v", event)
+	}
+	want := map[string]string{`a"b`: `[ "}", {"c":"]"} ]`, "n": `-1.5e3`, "ok": `true`, "nil": `null`}
+	if len(event.Extra) != len(want) {
+		t.Fatalf("unexpected extra: %v", event.Extra)
+	}
+	for k, v := range want {
+		if string(event.Extra[k]) != v {
+			t.Errorf("extra[%q] = %s, want %s", k, event.Extra[k], v)
+		}
+	}
+
+	var known Event
+	if err := json.Unmarshal([]byte(`{"type":"response.audio.delta","delta":"AAAA"}`), &known); err != nil || known.Extra != nil {
+		t.Errorf("extra should be nil without unknown fields, got %v, %v", known.Extra, err)
+	}
+}
+
+// audioDeltaEvent 100ms 24kHz 16bit 单声道音频的 response.audio.delta
+var audioDeltaEvent = []byte(`{"type":"response.audio.delta","event_id":"event_1","response_id":"resp_1","item_id":"item_1","output_index":0,"content_index":0,"delta":"` +
+	base64.StdEncoding.EncodeToString(make([]byte, 4800)) + `"}`)
+
+var responseDoneEvent = []byte(`{"type":"response.done","event_id":"event_2","response":{"id":"resp_1","object":"realtime.response","status":"completed",` +
+	`"output":[{"id":"item_1","object":"realtime.item","type":"message","status":"completed","role":"assistant","content":[{"type":"audio","transcript":"你好，有什么可以帮你"}]}],` +
+	`"usage":{"total_tokens":120,"input_tokens":80,"output_tokens":40}}}`)
+
+func BenchmarkUnmarshalAudioDelta(b *testing.B) {
+	benchmarkUnmarshal(b, audioDeltaEvent, func() any { return &Event{} })
+}
+
+// BenchmarkUnmarshalAudioDeltaBaseline 不保留未声明字段的解析，作为对照
+func BenchmarkUnmarshalAudioDeltaBaseline(b *testing.B) {
+	type plain Event
+	benchmarkUnmarshal(b, audioDeltaEvent, func() any { return &plain{} })
+}
+
+// BenchmarkDecodeAudioDelta 客户端直接调用 UnmarshalJSON，省去 json.Unmarshal 的预先校验
+func BenchmarkDecodeAudioDelta(b *testing.B) {
+	b.SetBytes(int64(len(audioDeltaEvent)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := (&Event{}).UnmarshalJSON(audioDeltaEvent); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalResponseDone(b *testing.B) {
+	benchmarkUnmarshal(b, responseDoneEvent, func() any { return &Event{} })
+}
+
+func benchmarkUnmarshal(b *testing.B, data []byte, newValue func() any) {
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := json.Unmarshal(data, newValue()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+)
+
+// AppendAudioEvent 将 input_audio_buffer.append 事件序列化后追加到 dst，audio 为原始音频数据，
+// 直接 base64 编码写入 dst，不经过反射和中间字符串。dst 容量足够时不产生内存分配
+func AppendAudioEvent(dst []byte, eventID string, audio []byte, clientTimestamp int64) []byte {
+	dst = appendEventHead(dst, eventID, RealtimeClientEventInputAudioBufferAppend)
+	dst = append(dst, `,"audio":"`...)
+	dst = appendBase64(dst, audio)
+	dst = append(dst, `","delta":""`...)
+	return appendEventTail(dst, clientTimestamp)
+}
+
+// AppendVideoFrameEvent 将 input_audio_buffer.append_video_frame 事件序列化后追加到 dst，行为同 AppendAudioEvent
+func AppendVideoFrameEvent(dst []byte, eventID string, frame []byte, clientTimestamp int64) []byte {
+	dst = appendEventHead(dst, eventID, RealtimeClientVideoAppend)
+	dst = append(dst, `,"delta":""`...)
+	if clientTimestamp != 0 {
+		dst = append(dst, `,"client_timestamp":`...)
+		dst = strconv.AppendInt(dst, clientTimestamp, 10)
+	}
+	if len(frame) > 0 {
+		dst = append(dst, `,"video_frame":"`...)
+		dst = appendBase64(dst, frame)
+		dst = append(dst, '"')
+	}
+	return append(dst, '}')
+}
+
+// AppendEvent 对只包含音频或视频帧数据的追加事件使用快速编码并追加到 dst，输出与 json.Marshal 一致。
+// 其他事件或设置了额外字段的事件返回 false，此时应使用 json.Marshal
+func AppendEvent(dst []byte, e *Event) ([]byte, bool) {
+	if !isMediaOnly(e) {
+		return dst, false
+	}
+	switch e.Type {
+	case RealtimeClientEventInputAudioBufferAppend:
+		if len(e.VideoFrame) > 0 {
+			return dst, false
+		}
+		dst = appendEventHead(dst, e.EventID, e.Type)
+		if e.Audio != "" {
+			dst = append(dst, `,"audio":`...)
+			dst = appendJSONString(dst, e.Audio)
+		}
+		dst = append(dst, `,"delta":""`...)
+		return appendEventTail(dst, e.ClientTimestamp), true
+	case RealtimeClientVideoAppend:
+		if e.Audio != "" {
+			return dst, false
+		}
+		return AppendVideoFrameEvent(dst, e.EventID, e.VideoFrame, e.ClientTimestamp), true
+	}
+	return dst, false
+}
+
+// isMediaOnly 判断事件除 type、event_id、audio、video_frame、client_timestamp 外没有设置其他字段
+func isMediaOnly(e *Event) bool {
+	return e.Session == nil && e.Response == nil && e.ItemID == "" && e.PreviousItemID == "" &&
+		e.ResponseID == "" && e.OutputIndex == 0 && e.ContentIndex == 0 && e.Delta == "" &&
+		e.Item == nil && e.Text == nil && e.Transcript == nil && e.Name == "" && e.CallID == "" &&
+		e.Arguments == "" && e.Instructions == "" && e.Error == nil && e.Conversation == nil &&
+		e.Part == nil && e.AudioStartMS == 0 && e.AudioEndMS == 0 && e.RateLimits == nil &&
+		e.BetaFields == nil && len(e.Extra) == 0
+}
+
+func appendEventHead(dst []byte, eventID string, t EventType) []byte {
+	dst = append(dst, '{')
+	if eventID != "" {
+		dst = append(dst, `"event_id":`...)
+		dst = appendJSONString(dst, eventID)
+		dst = append(dst, ',')
+	}
+	dst = append(dst, `"type":`...)
+	return appendJSONString(dst, string(t))
+}
+
+func appendEventTail(dst []byte, clientTimestamp int64) []byte {
+	if clientTimestamp != 0 {
+		dst = append(dst, `,"client_timestamp":`...)
+		dst = strconv.AppendInt(dst, clientTimestamp, 10)
+	}
+	return append(dst, '}')
+}
+
+// appendBase64 将 data 以标准 base64 编码直接写入 dst 的空闲容量
+func appendBase64(dst []byte, data []byte) []byte {
+	n := base64.StdEncoding.EncodedLen(len(data))
+	dst = grow(dst, n)
+	base64.StdEncoding.Encode(dst[len(dst):len(dst)+n], data)
+	return dst[:len(dst)+n]
+}
+
+// appendJSONString 追加 JSON 字符串，不含需要转义的字符时直接写入，否则交给 json.Marshal 以保证与其输出一致
+func appendJSONString(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			quoted, _ := json.Marshal(s)
+			return append(dst, quoted...)
+		}
+	}
+	dst = grow(dst, len(s)+2)
+	dst = append(dst, '"')
+	dst = append(dst, s...)
+	return append(dst, '"')
+}
+
+func grow(dst []byte, n int) []byte {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+	grown := make([]byte, len(dst), 2*cap(dst)+n)
+	copy(grown, dst)
+	return grown
+}
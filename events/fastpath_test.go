@@ -0,0 +1,135 @@
+package events
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAppendEventMatchesMarshal(t *testing.T) {
+	audio := bytes.Repeat([]byte{1, 2, 3, 250}, 100)
+	for _, event := range []*Event{
+		{Type: RealtimeClientEventInputAudioBufferAppend, Audio: base64.StdEncoding.EncodeToString(audio), ClientTimestamp: 1700000000000},
+		{Type: RealtimeClientEventInputAudioBufferAppend, EventID: "evt_<1>", Audio: "AAEC"},
+		{Type: RealtimeClientVideoAppend, EventID: "evt_2", VideoFrame: audio, ClientTimestamp: 1},
+		{Type: RealtimeClientVideoAppend},
+	} {
+		want, err := json.Marshal(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := AppendEvent(nil, event)
+		if !ok {
+			t.Fatalf("%s: fast path not used", event.Type)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: output mismatch:\n got %s\nwant %s", event.Type, got, want)
+		}
+	}
+
+	got := AppendAudioEvent(nil, "evt_3", audio, 5)
+	want, _ := json.Marshal(&Event{Type: RealtimeClientEventInputAudioBufferAppend, EventID: "evt_3", Audio: base64.StdEncoding.EncodeToString(audio), ClientTimestamp: 5})
+	if !bytes.Equal(got, want) {
+		t.Errorf("AppendAudioEvent mismatch:\n got %s\nwant %s", got, want)
+	}
+}
+
+// TestAppendEventRejectsOtherFields 事件设置了其他字段时不能使用快速编码，新增字段后需同步更新 isMediaOnly
+func TestAppendEventRejectsOtherFields(t *testing.T) {
+	allowed := map[string]bool{"Type": true, "EventID": true, "Audio": true, "VideoFrame": true, "ClientTimestamp": true}
+	typ := reflect.TypeOf(Event{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if allowed[field.Name] {
+			continue
+		}
+		event := &Event{Type: RealtimeClientEventInputAudioBufferAppend, Audio: "AAEC"}
+		v := reflect.ValueOf(event).Elem().Field(i)
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString("x")
+		case reflect.Int, reflect.Int64:
+			v.SetInt(1)
+		case reflect.Pointer:
+			v.Set(reflect.New(field.Type.Elem()))
+		case reflect.Slice:
+			v.Set(reflect.MakeSlice(field.Type, 1, 1))
+		case reflect.Map:
+			m := reflect.MakeMap(field.Type)
+			m.SetMapIndex(reflect.ValueOf("x"), reflect.Zero(field.Type.Elem()))
+			v.Set(m)
+		default:
+			t.Fatalf("field %s: unhandled kind %s", field.Name, v.Kind())
+		}
+		if _, ok := AppendEvent(nil, event); ok {
+			t.Errorf("field %s is set but fast path was used", field.Name)
+		}
+	}
+	// 音频事件只编码 audio，同时带有视频帧时也不能使用快速编码
+	event := &Event{Type: RealtimeClientEventInputAudioBufferAppend, Audio: "AAEC", VideoFrame: []byte{1}}
+	if _, ok := AppendEvent(nil, event); ok {
+		t.Error("audio event with video_frame must not use the fast path")
+	}
+	event = &Event{Type: RealtimeClientVideoAppend, Audio: "AAEC", VideoFrame: []byte{1}}
+	if _, ok := AppendEvent(nil, event); ok {
+		t.Error("video frame event with audio must not use the fast path")
+	}
+}
+
+func TestAppendAudioEventAllocs(t *testing.T) {
+	audio := make([]byte, 3200)
+	buf := make([]byte, 0, 8<<10)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendAudioEvent(buf[:0], "", audio, 1700000000000)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendAudioEvent allocated %v times per run", allocs)
+	}
+}
+
+// 100ms 16kHz 16bit 单声道 PCM
+var benchAudio = make([]byte, 3200)
+
+func BenchmarkAudioAppendMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		event := &Event{
+			Type:            RealtimeClientEventInputAudioBufferAppend,
+			Audio:           base64.StdEncoding.EncodeToString(benchAudio),
+			ClientTimestamp: 1700000000000,
+		}
+		if _, err := json.Marshal(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAudioAppendFast(b *testing.B) {
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		buf = AppendAudioEvent(buf[:0], "", benchAudio, 1700000000000)
+	}
+}
+
+func BenchmarkVideoFrameMarshal(b *testing.B) {
+	frame := make([]byte, 64<<10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		event := &Event{Type: RealtimeClientVideoAppend, VideoFrame: frame, ClientTimestamp: 1700000000000}
+		if _, err := json.Marshal(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVideoFrameFast(b *testing.B) {
+	frame := make([]byte, 64<<10)
+	b.ReportAllocs()
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		buf = AppendVideoFrameEvent(buf[:0], "", frame, 1700000000000)
+	}
+}
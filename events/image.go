@@ -0,0 +1,198 @@
+package events
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+)
+
+// MaxImageBytes input_image 内容中图片的大小上限
+const MaxImageBytes = 10 << 20
+
+// ImageEncoding input_image 内容中图片数据的编码方式
+type ImageEncoding int
+
+const (
+	// ImageEncodingDataURL 以 data:image/...;base64,... 形式写入 image_url
+	ImageEncodingDataURL ImageEncoding = iota
+	// ImageEncodingRaw 将原始图片数据写入 image，序列化时为 base64
+	ImageEncodingRaw
+)
+
+// supportedImageTypes 可作为 input_image 发送的图片格式
+var supportedImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// ImageOptions NewImageContent 的选项，nil 表示以 data URL 原样发送
+type ImageOptions struct {
+	Encoding ImageEncoding
+	// MaxWidth、MaxHeight 图片超出时按比例缩小，0 表示不限制。webp 不支持缩放
+	MaxWidth  int
+	MaxHeight int
+	// Quality 缩放后重新编码为 JPEG 时的质量，默认 85
+	Quality int
+}
+
+// NewImageContent 根据图片数据创建 input_image 内容，自动识别 MIME 类型，并按选项缩小尺寸
+func NewImageContent(data []byte, opts *ImageOptions) (Content, error) {
+	if opts == nil {
+		opts = &ImageOptions{}
+	}
+	mime, err := SniffImageType(data)
+	if err != nil {
+		return Content{}, err
+	}
+	if opts.MaxWidth > 0 || opts.MaxHeight > 0 {
+		if data, mime, err = downscaleImage(data, mime, opts); err != nil {
+			return Content{}, err
+		}
+	}
+	if len(data) > MaxImageBytes {
+		return Content{}, fmt.Errorf("image must be at most %d bytes, got %d", MaxImageBytes, len(data))
+	}
+	if opts.Encoding == ImageEncodingRaw {
+		return Content{Type: ContentTypeInputImage, Image: data}, nil
+	}
+	return Content{Type: ContentTypeInputImage, ImageURL: EncodeDataURL(mime, data)}, nil
+}
+
+// NewImageURLContent 使用已有的图片地址（data URL 或 http(s) 地址）创建 input_image 内容
+func NewImageURLContent(url string) Content {
+	return Content{Type: ContentTypeInputImage, ImageURL: url}
+}
+
+// ImageData 返回 input_image 内容中的图片数据及 MIME 类型，http(s) 地址的图片返回错误
+func (c Content) ImageData() (string, []byte, error) {
+	if len(c.Image) > 0 {
+		mime, err := SniffImageType(c.Image)
+		return mime, c.Image, err
+	}
+	if c.ImageURL == "" {
+		return "", nil, fmt.Errorf("content has no image")
+	}
+	return DecodeDataURL(c.ImageURL)
+}
+
+// SniffImageType 根据文件头识别图片的 MIME 类型，不支持的格式返回错误
+func SniffImageType(data []byte) (string, error) {
+	mime := http.DetectContentType(data)
+	for _, t := range supportedImageTypes {
+		if mime == t {
+			return mime, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported image type %s", mime)
+}
+
+// EncodeDataURL 将数据编码为 data:<mime>;base64,... 形式
+func EncodeDataURL(mime string, data []byte) string {
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeDataURL 解析 base64 编码的 data URL，返回 MIME 类型和数据
+func DecodeDataURL(url string) (string, []byte, error) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return "", nil, fmt.Errorf("not a data url")
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", nil, fmt.Errorf("data url missing ','")
+	}
+	mime, ok := strings.CutSuffix(header, ";base64")
+	if !ok {
+		return "", nil, fmt.Errorf("data url is not base64 encoded")
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("decode data url failed: %w", err)
+	}
+	return mime, data, nil
+}
+
+// downscaleImage 将图片等比缩小到 MaxWidth x MaxHeight 以内，未超出时原样返回。
+// PNG 和 GIF 缩放后编码为 PNG 以保留透明度，JPEG 仍编码为 JPEG
+func downscaleImage(data []byte, mime string, opts *ImageOptions) ([]byte, string, error) {
+	if mime == "image/webp" {
+		return nil, "", fmt.Errorf("resizing image/webp is not supported")
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image failed: %w", err)
+	}
+	width, height := fitSize(cfg.Width, cfg.Height, opts.MaxWidth, opts.MaxHeight)
+	if width == cfg.Width && height == cfg.Height {
+		return data, mime, nil
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image failed: %w", err)
+	}
+	dst := resizeBox(src, width, height)
+
+	var buf bytes.Buffer
+	if mime == "image/jpeg" {
+		quality := opts.Quality
+		if quality <= 0 {
+			quality = 85
+		}
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: quality})
+	} else {
+		mime = "image/png"
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("encode image failed: %w", err)
+	}
+	return buf.Bytes(), mime, nil
+}
+
+// fitSize 计算等比缩小到 maxWidth x maxHeight 以内的尺寸，0 表示该方向不限制
+func fitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+	if scale >= 1 {
+		return width, height
+	}
+	return max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale))
+}
+
+// resizeBox 使用区域平均的方式缩小图片，标准库没有缩放实现
+func resizeBox(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewImageContent(t *testing.T) {
+	data := testPNG(t, 200, 100)
+
+	c, err := NewImageContent(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mime, decoded, err := c.ImageData()
+	if err != nil || mime != "image/png" || !bytes.Equal(decoded, data) {
+		t.Errorf("ImageData = %s, %d bytes, %v", mime, len(decoded), err)
+	}
+
+	c, err = NewImageContent(data, &ImageOptions{Encoding: ImageEncodingRaw, MaxWidth: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ImageURL != "" || len(c.Image) == 0 {
+		t.Fatalf("raw encoding should fill Image: %+v", c)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(c.Image))
+	if err != nil || cfg.Width != 50 || cfg.Height != 25 {
+		t.Errorf("downscaled to %dx%d, %v", cfg.Width, cfg.Height, err)
+	}
+
+	out, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed Content
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Type != ContentTypeInputImage || !bytes.Equal(parsed.Image, c.Image) {
+		t.Errorf("round trip mismatch: %s", out)
+	}
+
+	if _, err := NewImageContent([]byte("not an image"), nil); err == nil {
+		t.Error("text should be rejected")
+	}
+}
+
+func TestValidateImageContent(t *testing.T) {
+	valid, err := NewImageContent(testPNG(t, 4, 4), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		content Content
+		ok      bool
+	}{
+		{valid, true},
+		{NewImageURLContent("https://example.com/a.jpg"), true},
+		{NewImageURLContent("ftp://example.com/a.jpg"), false},
+		{NewImageURLContent("data:image/png;base64,%%%"), false},
+		{Content{Type: ContentTypeInputImage}, false},
+	} {
+		event := Event{
+			Type: RealtimeClientEventConversationItemCreate,
+			Item: &Item{Type: ItemTypeMessage, Role: ItemRoleUser, Content: []Content{tc.content}},
+		}
+		if err := event.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%.40s: unexpected result %v", tc.content.ImageURL, err)
+		}
+	}
+}
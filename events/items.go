@@ -1,5 +1,10 @@
 package events
 
+import (
+	"encoding/json"
+	"reflect"
+)
+
 type ContentType string
 
 const (
@@ -7,12 +12,34 @@ const (
 	ContentTypeAudio      ContentType = "audio"
 	ContentTypeInputText  ContentType = "input_text"
 	ContentTypeInputAudio ContentType = "input_audio"
+	ContentTypeInputImage ContentType = "input_image"
 )
 
 type Content struct {
 	Type       ContentType `json:"type,omitempty"`
 	Transcript *string     `json:"transcript,omitempty"`
 	Text       *string     `json:"text,omitempty"`
+	ImageURL   string      `json:"image_url,omitempty"` // input_image 的图片地址，支持 data URL 和 http(s) 地址
+	Image      []byte      `json:"image,omitempty"`     // input_image 的原始图片数据，序列化时为 base64
+	// Extra 未声明的字段，解析时保留，序列化时原样输出
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (c Content) MarshalJSON() ([]byte, error) {
+	type alias Content
+	return marshalWithExtra(alias(c), c.Extra)
+}
+
+func (c *Content) UnmarshalJSON(data []byte) error {
+	type alias Content
+	var a alias
+	extra, err := unmarshalWithExtra(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*c = Content(a)
+	c.Extra = extra
+	return nil
 }
 
 type ItemStatus string
@@ -56,4 +83,23 @@ type Item struct {
 	Name      string     `json:"name,omitempty"`
 	CallId    string     `json:"call_id,omitempty"`
 	Arguments string     `json:"arguments,omitempty"`
+	// Extra 未声明的字段，解析时保留，序列化时原样输出
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (i Item) MarshalJSON() ([]byte, error) {
+	type alias Item
+	return marshalWithExtra(alias(i), i.Extra)
+}
+
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type alias Item
+	var a alias
+	extra, err := unmarshalWithExtra(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*i = Item(a)
+	i.Extra = extra
+	return nil
 }
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional 可区分未设置、null 和零值的字段：零值表示未设置，所在结构体序列化时省略该字段；
+// Null 序列化为 null；Some 序列化为对应的值，包括零值。
+// 例如客户端 VAD 模式需要发送 "turn_detection": null，可设置 TurnDetection: Null[TurnDetection]()。
+// Optional 是值类型，复制包含它的结构体不会共享数据
+type Optional[T any] struct {
+	set  bool
+	null bool
+	v    T
+}
+
+// Some 返回设置为 v 的字段
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{set: true, v: v}
+}
+
+// Null 返回显式设置为 null 的字段
+func Null[T any]() Optional[T] {
+	return Optional[T]{set: true, null: true}
+}
+
+// Get 返回字段的值，未设置或为 null 时 ok 为 false
+func (o Optional[T]) Get() (v T, ok bool) {
+	if !o.set || o.null {
+		return v, false
+	}
+	return o.v, true
+}
+
+// Or 返回字段的值，未设置或为 null 时返回 fallback
+func (o Optional[T]) Or(fallback T) T {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return fallback
+}
+
+// IsSet 判断字段是否已设置，显式设置为 null 也视为已设置
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsNull 判断字段是否显式设置为 null
+func (o Optional[T]) IsNull() bool {
+	return o.set && o.null
+}
+
+// omit 供所在结构体的 MarshalJSON 使用，未设置时返回 nil，配合 omitempty 省略字段
+func (o Optional[T]) omit() *Optional[T] {
+	if !o.set {
+		return nil
+	}
+	return &o
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	v, ok := o.Get()
+	if !ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
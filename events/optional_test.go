@@ -0,0 +1,81 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalSessionFields(t *testing.T) {
+	data := `{"type":"session.update","session":{"input_audio_format":"wav","turn_detection":null,"temperature":0},"delta":""}`
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatal(err)
+	}
+	if !event.Session.TurnDetection.IsNull() {
+		t.Error("turn_detection should be null")
+	}
+	if temperature, ok := event.Session.Temperature.Get(); !ok || temperature != 0 {
+		t.Errorf("temperature should be set to 0, got %v %v", temperature, ok)
+	}
+	if event.Session.InputAudioTranscription.IsSet() {
+		t.Error("input_audio_transcription should be unset")
+	}
+	out, err := json.Marshal(&event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != data {
+		t.Errorf("round trip mismatch:\n got %s\nwant %s", out, data)
+	}
+
+	session := Session{TurnDetection: Some(TurnDetection{
+		Type:           "server_vad",
+		Threshold:      Some(0.0),
+		CreateResponse: Some(false),
+	})}
+	out, err = json.Marshal(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"turn_detection":{"type":"server_vad","threshold":0,"create_response":false}}`
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+	detection, _ := session.TurnDetection.Get()
+	if detection.InterruptResponse.Or(true) != true || detection.CreateResponse.Or(true) != false {
+		t.Error("unexpected Or result")
+	}
+}
+
+func TestOptionalCopyDoesNotAlias(t *testing.T) {
+	original := Session{
+		Temperature:   Some(0.8),
+		TurnDetection: Some(TurnDetection{Type: "server_vad", Threshold: Some(0.5)}),
+	}
+	copied := original
+	detection, _ := copied.TurnDetection.Get()
+	detection.Threshold = Some(0.9)
+	copied.TurnDetection = Some(detection)
+	copied.Temperature = Null[float64]()
+
+	detection, _ = original.TurnDetection.Get()
+	if detection.Threshold.Or(0) != 0.5 || original.Temperature.Or(0) != 0.8 {
+		t.Fatalf("original session changed: %+v", original)
+	}
+	// 只包含 Optional 的结构体可以比较
+	if detection == (TurnDetection{}) || Some(1) != Some(1) || Null[int]() == Some(0) {
+		t.Error("unexpected comparison result")
+	}
+
+	out, err := json.Marshal(Session{Temperature: Some(0.0), InputAudioNoiseReduction: Null[NoiseReduction]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"temperature":0,"input_audio_noise_reduction":null}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+	out, _ = json.Marshal(Response{ID: "resp_1"})
+	if want := `{"id":"resp_1"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
@@ -1,5 +1,10 @@
 package events
 
+import (
+	"encoding/json"
+	"reflect"
+)
+
 type ResponseObject string
 
 const (
@@ -15,19 +20,48 @@ const (
 )
 
 type Response struct {
-	ID                string         `json:"id,omitempty"`
-	Modalities        []Modality     `json:"modalities,omitempty"`
-	Object            ResponseObject `json:"object,omitempty"`
-	Status            ResponseStatus `json:"status,omitempty"`
-	Instructions      string         `json:"instructions,omitempty"`
-	Voice             string         `json:"voice,omitempty"`
-	OutputAudioFormat string         `json:"output_audio_format,omitempty"`
-	Tools             []Tool         `json:"tools,omitempty"`
-	ToolChoice        string         `json:"tool_choice,omitempty"`
-	Temperature       float64        `json:"temperature,omitempty"`
-	MaxOutputTokens   int            `json:"max_output_tokens,omitempty"`
-	Usage             *Usage         `json:"usage,omitempty"`
-	Output            []Item         `json:"output,omitempty"`
+	ID                string            `json:"id,omitempty"`
+	Modalities        []Modality        `json:"modalities,omitempty"`
+	Object            ResponseObject    `json:"object,omitempty"`
+	Status            ResponseStatus    `json:"status,omitempty"`
+	Instructions      string            `json:"instructions,omitempty"`
+	Voice             string            `json:"voice,omitempty"`
+	OutputAudioFormat string            `json:"output_audio_format,omitempty"`
+	Tools             []Tool            `json:"tools,omitempty"`
+	ToolChoice        string            `json:"tool_choice,omitempty"`
+	Temperature       Optional[float64] `json:"temperature,omitempty"`
+	MaxOutputTokens   Optional[int]     `json:"max_output_tokens,omitempty"`
+	Usage             *Usage            `json:"usage,omitempty"`
+	Output            []Item            `json:"output,omitempty"`
+	// Metadata response.create 中附带的键值对，服务端会在 response.created 等事件中原样返回
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Extra 未声明的字段，解析时保留，序列化时原样输出
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	type alias Response
+	return marshalWithExtra(struct {
+		alias
+		Temperature     *Optional[float64] `json:"temperature,omitempty"`
+		MaxOutputTokens *Optional[int]     `json:"max_output_tokens,omitempty"`
+	}{
+		alias:           alias(r),
+		Temperature:     r.Temperature.omit(),
+		MaxOutputTokens: r.MaxOutputTokens.omit(),
+	}, r.Extra)
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type alias Response
+	var a alias
+	extra, err := unmarshalWithExtra(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*r = Response(a)
+	r.Extra = extra
+	return nil
 }
 
 // 上游返回的token使用情况
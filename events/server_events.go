@@ -0,0 +1,573 @@
+package events
+
+var serverEventTypes = map[EventType]func() ServerEvent{
+	RealtimeServerEventError:                                            func() ServerEvent { return &ErrorEvent{} },
+	RealtimeServerEventSessionCreated:                                   func() ServerEvent { return &SessionCreatedEvent{} },
+	RealtimeServerEventSessionUpdated:                                   func() ServerEvent { return &SessionUpdatedEvent{} },
+	RealtimeServerEventTranscriptionSessionUpdated:                      func() ServerEvent { return &TranscriptionSessionUpdatedEvent{} },
+	RealtimeServerEventConversationCreated:                              func() ServerEvent { return &ConversationCreatedEvent{} },
+	RealtimeServerEventConversationItemCreated:                          func() ServerEvent { return &ConversationItemCreatedEvent{} },
+	RealtimeServerEventConversationItemRetrieved:                        func() ServerEvent { return &ConversationItemRetrievedEvent{} },
+	RealtimeServerEventConversationItemInputAudioTranscriptionDelta:     func() ServerEvent { return &InputAudioTranscriptionDeltaEvent{} },
+	RealtimeServerEventConversationItemInputAudioTranscriptionCompleted: func() ServerEvent { return &InputAudioTranscriptionCompletedEvent{} },
+	RealtimeServerEventConversationItemInputAudioTranscriptionFailed:    func() ServerEvent { return &InputAudioTranscriptionFailedEvent{} },
+	RealtimeServerEventConversationItemTruncated:                        func() ServerEvent { return &ConversationItemTruncatedEvent{} },
+	RealtimeServerEventConversationItemDeleted:                          func() ServerEvent { return &ConversationItemDeletedEvent{} },
+	RealtimeServerEventInputAudioBufferCommitted:                        func() ServerEvent { return &InputAudioBufferCommittedEvent{} },
+	RealtimeServerEventInputAudioBufferCleared:                          func() ServerEvent { return &InputAudioBufferClearedEvent{} },
+	RealtimeServerEventInputAudioBufferSpeechStarted:                    func() ServerEvent { return &SpeechStartedEvent{} },
+	RealtimeServerEventInputAudioBufferSpeechStopped:                    func() ServerEvent { return &SpeechStoppedEvent{} },
+	RealtimeServerEventResponseCreated:                                  func() ServerEvent { return &ResponseCreatedEvent{} },
+	RealtimeServerEventResponseDone:                                     func() ServerEvent { return &ResponseDoneEvent{} },
+	RealtimeServerEventResponseOutputItemAdded:                          func() ServerEvent { return &ResponseOutputItemAddedEvent{} },
+	RealtimeServerEventResponseOutputItemDone:                           func() ServerEvent { return &ResponseOutputItemDoneEvent{} },
+	RealtimeServerEventResponseContentPartAdded:                         func() ServerEvent { return &ResponseContentPartAddedEvent{} },
+	RealtimeServerEventResponseContentPartDone:                          func() ServerEvent { return &ResponseContentPartDoneEvent{} },
+	RealtimeServerEventResponseTextDelta:                                func() ServerEvent { return &ResponseTextDeltaEvent{} },
+	RealtimeServerEventResponseTextDone:                                 func() ServerEvent { return &ResponseTextDoneEvent{} },
+	RealtimeServerEventResponseAudioTranscriptDelta:                     func() ServerEvent { return &ResponseAudioTranscriptDeltaEvent{} },
+	RealtimeServerEventResponseAudioTranscriptDone:                      func() ServerEvent { return &ResponseAudioTranscriptDoneEvent{} },
+	RealtimeServerEventResponseAudioDelta:                               func() ServerEvent { return &ResponseAudioDeltaEvent{} },
+	RealtimeServerEventResponseAudioDone:                                func() ServerEvent { return &ResponseAudioDoneEvent{} },
+	RealtimeServerEventResponseFunctionCallArgumentsDelta:               func() ServerEvent { return &FunctionCallArgumentsDeltaEvent{} },
+	RealtimeServerEventResponseFunctionCallArgumentsDone:                func() ServerEvent { return &FunctionCallArgumentsDoneEvent{} },
+	RealtimeServerEventRateLimitsUpdated:                                func() ServerEvent { return &RateLimitsUpdatedEvent{} },
+	RealtimeServerResponseFunctionCallSimpleBrowserEvent:                func() ServerEvent { return &SimpleBrowserEvent{} },
+	RealtimeServerResponseFunctionCallSimpleBrowserResultEvent:          func() ServerEvent { return &SimpleBrowserResultEvent{} },
+}
+
+// ErrorEvent error
+type ErrorEvent struct {
+	EventID string     `json:"event_id,omitempty"`
+	Error   EventError `json:"error"`
+}
+
+func (ErrorEvent) EventType() EventType { return RealtimeServerEventError }
+func (ErrorEvent) serverEvent()         {}
+func (e ErrorEvent) MarshalJSON() ([]byte, error) {
+	type alias ErrorEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SessionCreatedEvent session.created
+type SessionCreatedEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+func (SessionCreatedEvent) EventType() EventType { return RealtimeServerEventSessionCreated }
+func (SessionCreatedEvent) serverEvent()         {}
+func (e SessionCreatedEvent) MarshalJSON() ([]byte, error) {
+	type alias SessionCreatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SessionUpdatedEvent session.updated
+type SessionUpdatedEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+func (SessionUpdatedEvent) EventType() EventType { return RealtimeServerEventSessionUpdated }
+func (SessionUpdatedEvent) serverEvent()         {}
+func (e SessionUpdatedEvent) MarshalJSON() ([]byte, error) {
+	type alias SessionUpdatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// TranscriptionSessionUpdatedEvent transcription_session.updated
+type TranscriptionSessionUpdatedEvent struct {
+	EventID string  `json:"event_id,omitempty"`
+	Session Session `json:"session"`
+}
+
+func (TranscriptionSessionUpdatedEvent) EventType() EventType {
+	return RealtimeServerEventTranscriptionSessionUpdated
+}
+func (TranscriptionSessionUpdatedEvent) serverEvent() {}
+func (e TranscriptionSessionUpdatedEvent) MarshalJSON() ([]byte, error) {
+	type alias TranscriptionSessionUpdatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationCreatedEvent conversation.created
+type ConversationCreatedEvent struct {
+	EventID      string       `json:"event_id,omitempty"`
+	Conversation Conversation `json:"conversation"`
+}
+
+func (ConversationCreatedEvent) EventType() EventType { return RealtimeServerEventConversationCreated }
+func (ConversationCreatedEvent) serverEvent()         {}
+func (e ConversationCreatedEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationCreatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemCreatedEvent conversation.item.created
+type ConversationItemCreatedEvent struct {
+	EventID        string `json:"event_id,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	Item           Item   `json:"item"`
+}
+
+func (ConversationItemCreatedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemCreated
+}
+func (ConversationItemCreatedEvent) serverEvent() {}
+func (e ConversationItemCreatedEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemCreatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemRetrievedEvent conversation.item.retrieved
+type ConversationItemRetrievedEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	Item    Item   `json:"item"`
+}
+
+func (ConversationItemRetrievedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemRetrieved
+}
+func (ConversationItemRetrievedEvent) serverEvent() {}
+func (e ConversationItemRetrievedEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemRetrievedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioTranscriptionDeltaEvent conversation.item.input_audio_transcription.delta
+type InputAudioTranscriptionDeltaEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	Delta        string `json:"delta"`
+}
+
+func (InputAudioTranscriptionDeltaEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemInputAudioTranscriptionDelta
+}
+func (InputAudioTranscriptionDeltaEvent) serverEvent() {}
+func (e InputAudioTranscriptionDeltaEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioTranscriptionDeltaEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioTranscriptionCompletedEvent conversation.item.input_audio_transcription.completed
+type InputAudioTranscriptionCompletedEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	Transcript   string `json:"transcript"`
+}
+
+func (InputAudioTranscriptionCompletedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemInputAudioTranscriptionCompleted
+}
+func (InputAudioTranscriptionCompletedEvent) serverEvent() {}
+func (e InputAudioTranscriptionCompletedEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioTranscriptionCompletedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioTranscriptionFailedEvent conversation.item.input_audio_transcription.failed
+type InputAudioTranscriptionFailedEvent struct {
+	EventID      string     `json:"event_id,omitempty"`
+	ItemID       string     `json:"item_id"`
+	ContentIndex int        `json:"content_index"`
+	Error        EventError `json:"error"`
+}
+
+func (InputAudioTranscriptionFailedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemInputAudioTranscriptionFailed
+}
+func (InputAudioTranscriptionFailedEvent) serverEvent() {}
+func (e InputAudioTranscriptionFailedEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioTranscriptionFailedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemTruncatedEvent conversation.item.truncated
+type ConversationItemTruncatedEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ItemID       string `json:"item_id"`
+	ContentIndex int    `json:"content_index"`
+	AudioEndMS   int64  `json:"audio_end_ms"`
+}
+
+func (ConversationItemTruncatedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemTruncated
+}
+func (ConversationItemTruncatedEvent) serverEvent() {}
+func (e ConversationItemTruncatedEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemTruncatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ConversationItemDeletedEvent conversation.item.deleted
+type ConversationItemDeletedEvent struct {
+	EventID string `json:"event_id,omitempty"`
+	ItemID  string `json:"item_id"`
+}
+
+func (ConversationItemDeletedEvent) EventType() EventType {
+	return RealtimeServerEventConversationItemDeleted
+}
+func (ConversationItemDeletedEvent) serverEvent() {}
+func (e ConversationItemDeletedEvent) MarshalJSON() ([]byte, error) {
+	type alias ConversationItemDeletedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferCommittedEvent input_audio_buffer.committed
+type InputAudioBufferCommittedEvent struct {
+	EventID        string `json:"event_id,omitempty"`
+	PreviousItemID string `json:"previous_item_id,omitempty"`
+	ItemID         string `json:"item_id"`
+}
+
+func (InputAudioBufferCommittedEvent) EventType() EventType {
+	return RealtimeServerEventInputAudioBufferCommitted
+}
+func (InputAudioBufferCommittedEvent) serverEvent() {}
+func (e InputAudioBufferCommittedEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferCommittedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// InputAudioBufferClearedEvent input_audio_buffer.cleared
+type InputAudioBufferClearedEvent struct {
+	EventID string `json:"event_id,omitempty"`
+}
+
+func (InputAudioBufferClearedEvent) EventType() EventType {
+	return RealtimeServerEventInputAudioBufferCleared
+}
+func (InputAudioBufferClearedEvent) serverEvent() {}
+func (e InputAudioBufferClearedEvent) MarshalJSON() ([]byte, error) {
+	type alias InputAudioBufferClearedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SpeechStartedEvent input_audio_buffer.speech_started
+type SpeechStartedEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	AudioStartMS int64  `json:"audio_start_ms"`
+	ItemID       string `json:"item_id"`
+}
+
+func (SpeechStartedEvent) EventType() EventType {
+	return RealtimeServerEventInputAudioBufferSpeechStarted
+}
+func (SpeechStartedEvent) serverEvent() {}
+func (e SpeechStartedEvent) MarshalJSON() ([]byte, error) {
+	type alias SpeechStartedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SpeechStoppedEvent input_audio_buffer.speech_stopped
+type SpeechStoppedEvent struct {
+	EventID    string `json:"event_id,omitempty"`
+	AudioEndMS int64  `json:"audio_end_ms"`
+	ItemID     string `json:"item_id"`
+}
+
+func (SpeechStoppedEvent) EventType() EventType {
+	return RealtimeServerEventInputAudioBufferSpeechStopped
+}
+func (SpeechStoppedEvent) serverEvent() {}
+func (e SpeechStoppedEvent) MarshalJSON() ([]byte, error) {
+	type alias SpeechStoppedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseCreatedEvent response.created
+type ResponseCreatedEvent struct {
+	EventID  string   `json:"event_id,omitempty"`
+	Response Response `json:"response"`
+}
+
+func (ResponseCreatedEvent) EventType() EventType { return RealtimeServerEventResponseCreated }
+func (ResponseCreatedEvent) serverEvent()         {}
+func (e ResponseCreatedEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseCreatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseDoneEvent response.done
+type ResponseDoneEvent struct {
+	EventID  string   `json:"event_id,omitempty"`
+	Response Response `json:"response"`
+}
+
+func (ResponseDoneEvent) EventType() EventType { return RealtimeServerEventResponseDone }
+func (ResponseDoneEvent) serverEvent()         {}
+func (e ResponseDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseOutputItemAddedEvent response.output_item.added
+type ResponseOutputItemAddedEvent struct {
+	EventID     string `json:"event_id,omitempty"`
+	ResponseID  string `json:"response_id"`
+	OutputIndex int    `json:"output_index"`
+	Item        Item   `json:"item"`
+}
+
+func (ResponseOutputItemAddedEvent) EventType() EventType {
+	return RealtimeServerEventResponseOutputItemAdded
+}
+func (ResponseOutputItemAddedEvent) serverEvent() {}
+func (e ResponseOutputItemAddedEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputItemAddedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseOutputItemDoneEvent response.output_item.done
+type ResponseOutputItemDoneEvent struct {
+	EventID     string `json:"event_id,omitempty"`
+	ResponseID  string `json:"response_id"`
+	OutputIndex int    `json:"output_index"`
+	Item        Item   `json:"item"`
+}
+
+func (ResponseOutputItemDoneEvent) EventType() EventType {
+	return RealtimeServerEventResponseOutputItemDone
+}
+func (ResponseOutputItemDoneEvent) serverEvent() {}
+func (e ResponseOutputItemDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputItemDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseContentPartAddedEvent response.content_part.added
+type ResponseContentPartAddedEvent struct {
+	EventID      string      `json:"event_id,omitempty"`
+	ResponseID   string      `json:"response_id"`
+	ItemID       string      `json:"item_id"`
+	OutputIndex  int         `json:"output_index"`
+	ContentIndex int         `json:"content_index"`
+	Part         ContentPart `json:"part"`
+}
+
+func (ResponseContentPartAddedEvent) EventType() EventType {
+	return RealtimeServerEventResponseContentPartAdded
+}
+func (ResponseContentPartAddedEvent) serverEvent() {}
+func (e ResponseContentPartAddedEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseContentPartAddedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseContentPartDoneEvent response.content_part.done
+type ResponseContentPartDoneEvent struct {
+	EventID      string      `json:"event_id,omitempty"`
+	ResponseID   string      `json:"response_id"`
+	ItemID       string      `json:"item_id"`
+	OutputIndex  int         `json:"output_index"`
+	ContentIndex int         `json:"content_index"`
+	Part         ContentPart `json:"part"`
+}
+
+func (ResponseContentPartDoneEvent) EventType() EventType {
+	return RealtimeServerEventResponseContentPartDone
+}
+func (ResponseContentPartDoneEvent) serverEvent() {}
+func (e ResponseContentPartDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseContentPartDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseTextDeltaEvent response.text.delta
+type ResponseTextDeltaEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Delta        string `json:"delta"`
+}
+
+func (ResponseTextDeltaEvent) EventType() EventType { return RealtimeServerEventResponseTextDelta }
+func (ResponseTextDeltaEvent) serverEvent()         {}
+func (e ResponseTextDeltaEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseTextDeltaEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseTextDoneEvent response.text.done
+type ResponseTextDoneEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Text         string `json:"text"`
+}
+
+func (ResponseTextDoneEvent) EventType() EventType { return RealtimeServerEventResponseTextDone }
+func (ResponseTextDoneEvent) serverEvent()         {}
+func (e ResponseTextDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseTextDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseAudioTranscriptDeltaEvent response.audio_transcript.delta
+type ResponseAudioTranscriptDeltaEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Delta        string `json:"delta"`
+}
+
+func (ResponseAudioTranscriptDeltaEvent) EventType() EventType {
+	return RealtimeServerEventResponseAudioTranscriptDelta
+}
+func (ResponseAudioTranscriptDeltaEvent) serverEvent() {}
+func (e ResponseAudioTranscriptDeltaEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseAudioTranscriptDeltaEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseAudioTranscriptDoneEvent response.audio_transcript.done
+type ResponseAudioTranscriptDoneEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Transcript   string `json:"transcript"`
+}
+
+func (ResponseAudioTranscriptDoneEvent) EventType() EventType {
+	return RealtimeServerEventResponseAudioTranscriptDone
+}
+func (ResponseAudioTranscriptDoneEvent) serverEvent() {}
+func (e ResponseAudioTranscriptDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseAudioTranscriptDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseAudioDeltaEvent response.audio.delta，Delta 为解码后的音频数据
+type ResponseAudioDeltaEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+	Delta        []byte `json:"delta"`
+}
+
+func (ResponseAudioDeltaEvent) EventType() EventType { return RealtimeServerEventResponseAudioDelta }
+func (ResponseAudioDeltaEvent) serverEvent()         {}
+func (e ResponseAudioDeltaEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseAudioDeltaEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// ResponseAudioDoneEvent response.audio.done
+type ResponseAudioDoneEvent struct {
+	EventID      string `json:"event_id,omitempty"`
+	ResponseID   string `json:"response_id"`
+	ItemID       string `json:"item_id"`
+	OutputIndex  int    `json:"output_index"`
+	ContentIndex int    `json:"content_index"`
+}
+
+func (ResponseAudioDoneEvent) EventType() EventType { return RealtimeServerEventResponseAudioDone }
+func (ResponseAudioDoneEvent) serverEvent()         {}
+func (e ResponseAudioDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias ResponseAudioDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// FunctionCallArgumentsDeltaEvent response.function_call_arguments.delta
+type FunctionCallArgumentsDeltaEvent struct {
+	EventID     string `json:"event_id,omitempty"`
+	ResponseID  string `json:"response_id"`
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+	CallID      string `json:"call_id"`
+	Delta       string `json:"delta"`
+}
+
+func (FunctionCallArgumentsDeltaEvent) EventType() EventType {
+	return RealtimeServerEventResponseFunctionCallArgumentsDelta
+}
+func (FunctionCallArgumentsDeltaEvent) serverEvent() {}
+func (e FunctionCallArgumentsDeltaEvent) MarshalJSON() ([]byte, error) {
+	type alias FunctionCallArgumentsDeltaEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// FunctionCallArgumentsDoneEvent response.function_call_arguments.done
+type FunctionCallArgumentsDoneEvent struct {
+	EventID     string `json:"event_id,omitempty"`
+	ResponseID  string `json:"response_id"`
+	ItemID      string `json:"item_id"`
+	OutputIndex int    `json:"output_index"`
+	CallID      string `json:"call_id"`
+	Name        string `json:"name"`
+	Arguments   string `json:"arguments"`
+}
+
+func (FunctionCallArgumentsDoneEvent) EventType() EventType {
+	return RealtimeServerEventResponseFunctionCallArgumentsDone
+}
+func (FunctionCallArgumentsDoneEvent) serverEvent() {}
+func (e FunctionCallArgumentsDoneEvent) MarshalJSON() ([]byte, error) {
+	type alias FunctionCallArgumentsDoneEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// RateLimitsUpdatedEvent rate_limits.updated
+type RateLimitsUpdatedEvent struct {
+	EventID    string      `json:"event_id,omitempty"`
+	RateLimits []RateLimit `json:"rate_limits"`
+}
+
+func (RateLimitsUpdatedEvent) EventType() EventType { return RealtimeServerEventRateLimitsUpdated }
+func (RateLimitsUpdatedEvent) serverEvent()         {}
+func (e RateLimitsUpdatedEvent) MarshalJSON() ([]byte, error) {
+	type alias RateLimitsUpdatedEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SimpleBrowserEvent response.function_call.simple_browser，搜索信息可通过 ToEvent 后调用 ParseSimpleBrowser 解析
+type SimpleBrowserEvent struct {
+	EventID    string      `json:"event_id,omitempty"`
+	ResponseID string      `json:"response_id,omitempty"`
+	ItemID     string      `json:"item_id,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Session    *Session    `json:"session,omitempty"`
+	BetaFields *BetaFields `json:"beta_fields,omitempty"`
+}
+
+func (SimpleBrowserEvent) EventType() EventType {
+	return RealtimeServerResponseFunctionCallSimpleBrowserEvent
+}
+func (SimpleBrowserEvent) serverEvent() {}
+func (e SimpleBrowserEvent) MarshalJSON() ([]byte, error) {
+	type alias SimpleBrowserEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
+
+// SimpleBrowserResultEvent response.function_call.simple_browser.result
+type SimpleBrowserResultEvent struct {
+	EventID    string      `json:"event_id,omitempty"`
+	ResponseID string      `json:"response_id,omitempty"`
+	ItemID     string      `json:"item_id,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Session    *Session    `json:"session,omitempty"`
+	BetaFields *BetaFields `json:"beta_fields,omitempty"`
+}
+
+func (SimpleBrowserResultEvent) EventType() EventType {
+	return RealtimeServerResponseFunctionCallSimpleBrowserResultEvent
+}
+func (SimpleBrowserResultEvent) serverEvent() {}
+func (e SimpleBrowserResultEvent) MarshalJSON() ([]byte, error) {
+	type alias SimpleBrowserResultEvent
+	return marshalTyped(e.EventType(), alias(e))
+}
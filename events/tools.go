@@ -1,5 +1,10 @@
 package events
 
+import (
+	"encoding/json"
+	"reflect"
+)
+
 type Tool struct {
 	Type        string         `json:"type"`
 	Name        string         `json:"name"`
@@ -7,13 +12,130 @@ type Tool struct {
 	Parameters  ToolParameters `json:"parameters"`
 }
 
+// ToolParameters 工具参数的 JSON Schema，未声明的关键字保存在 Extra 中并原样输出
 type ToolParameters struct {
-	Type       string                  `json:"type"`
-	Properties map[string]ToolProperty `json:"properties"`
-	Required   []string                `json:"required"`
+	Schema               string                     `json:"$schema,omitempty"`
+	Type                 string                     `json:"type"`
+	Description          string                     `json:"description,omitempty"`
+	Properties           map[string]ToolProperty    `json:"properties"`
+	Required             []string                   `json:"required"`
+	AdditionalProperties *AdditionalProperties      `json:"additionalProperties,omitempty"`
+	OneOf                []ToolProperty             `json:"oneOf,omitempty"`
+	AnyOf                []ToolProperty             `json:"anyOf,omitempty"`
+	AllOf                []ToolProperty             `json:"allOf,omitempty"`
+	Extra                map[string]json.RawMessage `json:"-"`
 }
 
+// ToolProperty 参数字段的 JSON Schema，未声明的关键字保存在 Extra 中并原样输出
 type ToolProperty struct {
-	Type        string `json:"type,omitempty"`
-	Description string `json:"description,omitempty"`
+	Title                string                  `json:"title,omitempty"`
+	Type                 string                  `json:"type,omitempty"`
+	Description          string                  `json:"description,omitempty"`
+	Enum                 []any                   `json:"enum,omitempty"`
+	Default              any                     `json:"default,omitempty"`
+	Format               string                  `json:"format,omitempty"`
+	ContentEncoding      string                  `json:"contentEncoding,omitempty"` // 如 base64
+	Pattern              string                  `json:"pattern,omitempty"`
+	Minimum              *float64                `json:"minimum,omitempty"`
+	Maximum              *float64                `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64                `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64                `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int                    `json:"minLength,omitempty"`
+	MaxLength            *int                    `json:"maxLength,omitempty"`
+	MinItems             *int                    `json:"minItems,omitempty"`
+	MaxItems             *int                    `json:"maxItems,omitempty"`
+	Properties           map[string]ToolProperty `json:"properties,omitempty"` // type 为 object 时的字段
+	Required             []string                `json:"required,omitempty"`
+	AdditionalProperties *AdditionalProperties   `json:"additionalProperties,omitempty"`
+	Items                *ToolProperty           `json:"items,omitempty"` // type 为 array 时的元素
+	OneOf                []ToolProperty          `json:"oneOf,omitempty"`
+	AnyOf                []ToolProperty          `json:"anyOf,omitempty"`
+	AllOf                []ToolProperty          `json:"allOf,omitempty"`
+	// 其他关键字（如 $ref、const、类型数组 "type": ["string", "null"]）原样保存
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AdditionalProperties additionalProperties 关键字，可以是布尔值或 schema
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *ToolProperty
+}
+
+// AllowAdditionalProperties 返回布尔形式的 additionalProperties
+func AllowAdditionalProperties(allowed bool) *AdditionalProperties {
+	return &AdditionalProperties{Allowed: allowed}
+}
+
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		*a = AdditionalProperties{Allowed: allowed}
+		return nil
+	}
+	schema := &ToolProperty{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return err
+	}
+	*a = AdditionalProperties{Allowed: true, Schema: schema}
+	return nil
+}
+
+func (p ToolParameters) MarshalJSON() ([]byte, error) {
+	type alias ToolParameters
+	return marshalWithExtra(alias(p), p.Extra)
+}
+
+func (p *ToolParameters) UnmarshalJSON(data []byte) error {
+	type alias ToolParameters
+	var a alias
+	extra, err := unmarshalSchema(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*p = ToolParameters(a)
+	p.Extra = extra
+	return nil
+}
+
+func (p ToolProperty) MarshalJSON() ([]byte, error) {
+	type alias ToolProperty
+	return marshalWithExtra(alias(p), p.Extra)
+}
+
+func (p *ToolProperty) UnmarshalJSON(data []byte) error {
+	type alias ToolProperty
+	var a alias
+	extra, err := unmarshalSchema(data, reflect.TypeOf(a), &a)
+	if err != nil {
+		return err
+	}
+	*p = ToolProperty(a)
+	p.Extra = extra
+	return nil
+}
+
+// unmarshalSchema 解码已声明的关键字并返回其余关键字，非字符串形式的 type 也作为其余关键字保存
+func unmarshalSchema(data []byte, t reflect.Type, v any) (map[string]json.RawMessage, error) {
+	known, extra, err := splitExtra(data, t)
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := known["type"]; ok && !isJSONString(raw) && string(raw) != "null" {
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra["type"] = raw
+		delete(known, "type")
+	}
+	if data, err = json.Marshal(known); err != nil {
+		return nil, err
+	}
+	return extra, json.Unmarshal(data, v)
 }
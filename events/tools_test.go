@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToolParametersRoundTrip(t *testing.T) {
+	// 取自 samples/files/Audio.ClientVad.FC.Input，并补充了嵌套结构和未声明的关键字
+	input := `{"$schema":"http://json-schema.org/draft-07/schema#","type":"object","properties":{` +
+		`"date":{"type":"string","format":"date","description":"要查询天气的日期"},` +
+		`"days":{"type":"integer","minimum":1,"maximum":7},` +
+		`"localtion":{"type":"string","description":"要查询天气的城市"},` +
+		`"options":{"type":"object","properties":{"unit":{"enum":["celsius","fahrenheit"]}},"additionalProperties":{"type":"string"}},` +
+		`"tags":{"type":"array","items":{"type":["string","null"]},"uniqueItems":true},` +
+		`"target":{"oneOf":[{"type":"string"},{"$ref":"#/definitions/geo"}]}},` +
+		`"required":["localtion"],"additionalProperties":false,"definitions":{"geo":{"type":"object"}}}`
+
+	var parameters ToolParameters
+	if err := json.Unmarshal([]byte(input), &parameters); err != nil {
+		t.Fatal(err)
+	}
+	if parameters.Schema != "http://json-schema.org/draft-07/schema#" || parameters.AdditionalProperties == nil || parameters.AdditionalProperties.Allowed {
+		t.Fatalf("unexpected parameters: %+v", parameters)
+	}
+	if days := parameters.Properties["days"]; *days.Minimum != 1 || *days.Maximum != 7 {
+		t.Fatalf("unexpected days property: %+v", days)
+	}
+	if options := parameters.Properties["options"]; options.AdditionalProperties.Schema == nil || options.AdditionalProperties.Schema.Type != "string" {
+		t.Fatalf("unexpected options property: %+v", options)
+	}
+
+	output, err := json.Marshal(parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got any
+	_ = json.Unmarshal([]byte(input), &want)
+	_ = json.Unmarshal(output, &got)
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(wantJSON) != string(gotJSON) {
+		t.Fatalf("round trip mismatch:\n%s\nwant:\n%s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// TypedEvent 强类型事件的公共接口，每个事件类型对应一个结构体，序列化时自动写入 type 字段
+type TypedEvent interface {
+	EventType() EventType
+}
+
+// ClientEvent 客户端事件，具体类型见 client_events.go
+type ClientEvent interface {
+	TypedEvent
+	clientEvent()
+}
+
+// ServerEvent 服务端事件，具体类型见 server_events.go。
+// ParseServerEvent 返回的是指针类型，例如 *ResponseAudioDeltaEvent
+type ServerEvent interface {
+	TypedEvent
+	serverEvent()
+}
+
+// Sender 发送客户端事件，client.RealtimeClient 满足该接口，供 toolcall、conversation 等包发回事件
+type Sender interface {
+	Send(event *Event) error
+}
+
+// UnknownEvent 尚无对应结构体的事件，保留原始 JSON，序列化时原样输出
+type UnknownEvent struct {
+	Type EventType
+	Raw  json.RawMessage
+}
+
+func (e UnknownEvent) EventType() EventType { return e.Type }
+func (UnknownEvent) clientEvent()           {}
+func (UnknownEvent) serverEvent()           {}
+
+func (e UnknownEvent) MarshalJSON() ([]byte, error) {
+	return e.Raw, nil
+}
+
+// ParseServerEvent 按 type 字段将服务端事件解析为对应的结构体，DefaultExtensions 中注册的自定义事件返回
+// *ExtensionEvent，其余未知类型返回 *UnknownEvent
+func ParseServerEvent(data []byte) (ServerEvent, error) {
+	typ, err := peekType(data)
+	if err != nil {
+		return nil, err
+	}
+	newEvent, ok := serverEventTypes[typ]
+	if !ok {
+		if event, err := DefaultExtensions.Decode(data); err != nil {
+			return nil, err
+		} else if event != nil {
+			return event, nil
+		}
+		return &UnknownEvent{Type: typ, Raw: append(json.RawMessage(nil), data...)}, nil
+	}
+	event := newEvent()
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("parse %s event failed: %w", typ, err)
+	}
+	return event, nil
+}
+
+// ParseClientEvent 按 type 字段将客户端事件解析为对应的结构体，自定义事件和未知类型的处理同 ParseServerEvent
+func ParseClientEvent(data []byte) (ClientEvent, error) {
+	typ, err := peekType(data)
+	if err != nil {
+		return nil, err
+	}
+	newEvent, ok := clientEventTypes[typ]
+	if !ok {
+		if event, err := DefaultExtensions.Decode(data); err != nil {
+			return nil, err
+		} else if event != nil {
+			return event, nil
+		}
+		return &UnknownEvent{Type: typ, Raw: append(json.RawMessage(nil), data...)}, nil
+	}
+	event := newEvent()
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("parse %s event failed: %w", typ, err)
+	}
+	return event, nil
+}
+
+// ToEvent 将强类型事件转换为通用的 Event，便于与现有接口配合使用
+func ToEvent(e TypedEvent) (*Event, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Typed 将通用的 Event 转换为对应的强类型服务端事件
+func (e *Event) Typed() (ServerEvent, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServerEvent(data)
+}
+
+func peekType(data []byte) (EventType, error) {
+	var head struct {
+		Type EventType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return "", fmt.Errorf("parse event failed: %w", err)
+	}
+	if head.Type == "" {
+		return "", fmt.Errorf("parse event failed: missing type")
+	}
+	return head.Type, nil
+}
+
+// marshalTyped 序列化事件结构体并在最前面写入 type 字段
+func marshalTyped(t EventType, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.Grow(len(data) + len(typ) + 10)
+	b.WriteString(`{"type":`)
+	b.Write(typ)
+	if len(data) > 2 {
+		b.WriteByte(',')
+		b.Write(data[1:])
+	} else {
+		b.WriteByte('}')
+	}
+	return b.Bytes(), nil
+}
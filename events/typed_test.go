@@ -0,0 +1,106 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseServerEvent(t *testing.T) {
+	event, err := ParseServerEvent([]byte(`{"event_id":"e1","type":"response.audio.delta","response_id":"r1","item_id":"i1","output_index":0,"content_index":0,"delta":"AAEC"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, ok := event.(*ResponseAudioDeltaEvent)
+	if !ok {
+		t.Fatalf("unexpected event type %T", event)
+	}
+	if !reflect.DeepEqual(audio.Delta, []byte{0, 1, 2}) || audio.ResponseID != "r1" || audio.EventID != "e1" {
+		t.Errorf("unexpected event: %+v", audio)
+	}
+
+	event, err = ParseServerEvent([]byte(`{"type":"response.text.delta","response_id":"r1","delta":"你好"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, ok := event.(*ResponseTextDeltaEvent); !ok || text.Delta != "你好" {
+		t.Errorf("unexpected event: %#v", event)
+	}
+
+	event, err = ParseServerEvent([]byte(`{"type":"server.custom","foo":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown, ok := event.(*UnknownEvent); !ok || unknown.Type != "server.custom" {
+		t.Errorf("unexpected event: %#v", event)
+	}
+	if _, err := ParseServerEvent([]byte(`{"delta":"x"}`)); err == nil {
+		t.Error("expected error for missing type")
+	}
+}
+
+func TestTypedEventRoundTrip(t *testing.T) {
+	for typ, newEvent := range serverEventTypes {
+		data, err := json.Marshal(newEvent())
+		if err != nil {
+			t.Fatalf("marshal %s: %v", typ, err)
+		}
+		event, err := ParseServerEvent(data)
+		if err != nil {
+			t.Fatalf("parse %s: %v", typ, err)
+		}
+		if event.EventType() != typ {
+			t.Errorf("%s parsed as %s", typ, event.EventType())
+		}
+	}
+	for typ, newEvent := range clientEventTypes {
+		data, err := json.Marshal(newEvent())
+		if err != nil {
+			t.Fatalf("marshal %s: %v", typ, err)
+		}
+		event, err := ParseClientEvent(data)
+		if err != nil {
+			t.Fatalf("parse %s: %v", typ, err)
+		}
+		if event.EventType() != typ {
+			t.Errorf("%s parsed as %s", typ, event.EventType())
+		}
+	}
+}
+
+func TestTypedClientEventMarshal(t *testing.T) {
+	data, err := json.Marshal(InputAudioBufferAppendEvent{Audio: []byte{0, 1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"input_audio_buffer.append","audio":"AAEC"}` {
+		t.Errorf("unexpected json: %s", data)
+	}
+	data, err = json.Marshal(&InputAudioBufferClearEvent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"type":"input_audio_buffer.clear"}` {
+		t.Errorf("unexpected json: %s", data)
+	}
+
+	event, err := ToEvent(ResponseCancelEvent{ResponseID: "r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != RealtimeClientEventResponseCancel || event.ResponseID != "r1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	typed, err := (&Event{Type: RealtimeServerEventResponseAudioTranscriptDone, ResponseID: "r1", Transcript: strPtr("好的")}).Typed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done, ok := typed.(*ResponseAudioTranscriptDoneEvent); !ok || done.Transcript != "好的" {
+		t.Errorf("unexpected event: %#v", typed)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
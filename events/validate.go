@@ -0,0 +1,238 @@
+package events
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxAudioAppendBytes 单个 input_audio_buffer.append 事件中音频数据解码后的大小上限
+	MaxAudioAppendBytes = 15 << 20
+	// MaxVideoFrameBytes 单个 input_audio_buffer.append_video_frame 事件中图片的大小上限
+	MaxVideoFrameBytes = 10 << 20
+)
+
+// FieldError 一个字段的校验错误，Field 为以 . 分隔的字段路径，数组下标以 [i] 表示
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError 客户端事件不合法，发送前即被拒绝
+type ValidationError struct {
+	EventType EventType    `json:"event_type"`
+	Errors    []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		messages = append(messages, fe.Field+": "+fe.Message)
+	}
+	return fmt.Sprintf("invalid %s event: %s", e.EventType, strings.Join(messages, "; "))
+}
+
+type eventValidator struct {
+	errors []FieldError
+}
+
+func (v *eventValidator) fail(field, format string, args ...any) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *eventValidator) required(field string, ok bool) {
+	if !ok {
+		v.fail(field, "is required")
+	}
+}
+
+func oneOf[T ~string](v *eventValidator, field string, value T, allowed ...T) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = string(a)
+	}
+	v.fail(field, "must be one of %s, got %q", strings.Join(names, ", "), value)
+}
+
+// Validate 校验客户端事件的必填字段、枚举值和大小限制，不合法时返回 *ValidationError。
+// 服务端事件和未知类型的事件不做校验
+func (e *Event) Validate() error {
+	v := &eventValidator{}
+	switch e.Type {
+	case "":
+		v.fail("type", "is required")
+	case RealtimeClientEventSessionUpdate, RealtimeClientEventTranscriptionSessionUpdate:
+		v.required("session", e.Session != nil)
+		if e.Session != nil {
+			v.validateSession("session", e.Session)
+		}
+	case RealtimeClientEventInputAudioBufferAppend:
+		v.required("audio", e.Audio != "")
+		if e.Audio != "" {
+			if err := checkBase64(e.Audio); err != nil {
+				v.fail("audio", "is not valid base64: %v", err)
+			} else if size := base64.StdEncoding.DecodedLen(len(e.Audio)); size > MaxAudioAppendBytes {
+				v.fail("audio", "must be at most %d bytes, got %d", MaxAudioAppendBytes, size)
+			}
+		}
+	case RealtimeClientVideoAppend:
+		v.required("video_frame", len(e.VideoFrame) > 0)
+		if len(e.VideoFrame) > MaxVideoFrameBytes {
+			v.fail("video_frame", "must be at most %d bytes, got %d", MaxVideoFrameBytes, len(e.VideoFrame))
+		}
+	case RealtimeClientEventConversationItemCreate:
+		v.required("item", e.Item != nil)
+		if e.Item != nil {
+			v.validateItem("item", e.Item)
+		}
+	case RealtimeClientEventConversationItemRetrieve, RealtimeClientEventConversationItemDelete:
+		v.required("item_id", e.ItemID != "")
+	case RealtimeClientEventConversationItemTruncate:
+		v.required("item_id", e.ItemID != "")
+		if e.ContentIndex < 0 {
+			v.fail("content_index", "must be >= 0")
+		}
+		if e.AudioEndMS < 0 {
+			v.fail("audio_end_ms", "must be >= 0")
+		}
+	case RealtimeClientEventResponseCreate:
+		if e.Response != nil {
+			v.validateModalities("response.modalities", e.Response.Modalities)
+			for i := range e.Response.Tools {
+				v.validateTool(fmt.Sprintf("response.tools[%d]", i), &e.Response.Tools[i])
+			}
+		}
+	}
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{EventType: e.Type, Errors: v.errors}
+}
+
+var base64Alphabet = func() (table [256]bool) {
+	for _, c := range []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/") {
+		table[c] = true
+	}
+	return table
+}()
+
+// checkBase64 直接检查字符集和填充位置，与 base64.StdEncoding.DecodeString 的校验结果一致：
+// 忽略 \r、\n，= 只能出现在最后一组的第 3、4 位，之后不能再有数据。不会分配解码后的数据
+func checkBase64(s string) error {
+	n, padded := 0, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\r' || c == '\n':
+			continue
+		case padded:
+			// 只允许 xx== 中的第二个 =
+			if c != '=' || n%4 != 3 {
+				return base64.CorruptInputError(i)
+			}
+		case c == '=':
+			if n%4 < 2 {
+				return base64.CorruptInputError(i)
+			}
+			padded = true
+		case !base64Alphabet[c]:
+			return base64.CorruptInputError(i)
+		}
+		n++
+	}
+	if n%4 != 0 {
+		return base64.CorruptInputError(len(s))
+	}
+	return nil
+}
+
+func (v *eventValidator) validateSession(field string, s *Session) {
+	v.validateModalities(field+".modalities", s.Modalities)
+	if td, ok := s.TurnDetection.Get(); ok {
+		v.required(field+".turn_detection.type", td.Type != "")
+	}
+	if nr, ok := s.InputAudioNoiseReduction.Get(); ok {
+		oneOf(v, field+".input_audio_noise_reduction.type", nr.Type, DenoiseTypeNearField, DenoiseTypeFarField)
+	}
+	if s.BetaFields != nil && s.BetaFields.ChatMode != "" {
+		oneOf(v, field+".beta_fields.chat_mode", s.BetaFields.ChatMode, ChatModeAudio, ChatModeVideoPassive, ChatModeVideoProactive)
+	}
+	if s.BetaFields != nil && s.BetaFields.FPS < 0 {
+		v.fail(field+".beta_fields.fps", "must be >= 0")
+	}
+	for i := range s.Tools {
+		v.validateTool(fmt.Sprintf("%s.tools[%d]", field, i), &s.Tools[i])
+	}
+}
+
+func (v *eventValidator) validateModalities(field string, modalities []Modality) {
+	for i, m := range modalities {
+		oneOf(v, fmt.Sprintf("%s[%d]", field, i), m, ModalityText, ModalityAudio, ModalityVideo)
+	}
+}
+
+func (v *eventValidator) validateTool(field string, t *Tool) {
+	v.required(field+".name", t.Name != "")
+	if t.Type != "" {
+		oneOf(v, field+".type", t.Type, "function")
+	}
+}
+
+func (v *eventValidator) validateItem(field string, item *Item) {
+	oneOf(v, field+".type", item.Type, ItemTypeMessage, ItemTypeFunctionCall, ItemTypeFunctionCallOutput)
+	if item.Status != "" {
+		oneOf(v, field+".status", item.Status, ItemStatusInProgress, ItemStatusCompleted, ItemStatusIncomplete)
+	}
+	switch item.Type {
+	case ItemTypeMessage:
+		oneOf(v, field+".role", item.Role, ItemRoleUser, ItemRoleAssistant, ItemRoleSystem)
+		v.required(field+".content", len(item.Content) > 0)
+		for i := range item.Content {
+			c := &item.Content[i]
+			path := fmt.Sprintf("%s.content[%d]", field, i)
+			oneOf(v, path+".type", c.Type, ContentTypeText, ContentTypeAudio, ContentTypeInputText, ContentTypeInputAudio, ContentTypeInputImage)
+			if c.Type == ContentTypeInputText || c.Type == ContentTypeText {
+				v.required(path+".text", c.Text != nil)
+			}
+			if c.Type == ContentTypeInputImage {
+				v.validateImage(path, c)
+			}
+		}
+	case ItemTypeFunctionCall:
+		v.required(field+".call_id", item.CallId != "")
+		v.required(field+".name", item.Name != "")
+	case ItemTypeFunctionCallOutput:
+		v.required(field+".call_id", item.CallId != "")
+		v.required(field+".output", item.Output != nil)
+	}
+}
+
+func (v *eventValidator) validateImage(field string, c *Content) {
+	if c.ImageURL == "" && len(c.Image) == 0 {
+		v.fail(field+".image_url", "is required")
+		return
+	}
+	if len(c.Image) > 0 {
+		if len(c.Image) > MaxImageBytes {
+			v.fail(field+".image", "must be at most %d bytes, got %d", MaxImageBytes, len(c.Image))
+		}
+		return
+	}
+	switch {
+	case strings.HasPrefix(c.ImageURL, "data:"):
+		if _, data, err := DecodeDataURL(c.ImageURL); err != nil {
+			v.fail(field+".image_url", "%v", err)
+		} else if len(data) > MaxImageBytes {
+			v.fail(field+".image_url", "must be at most %d bytes, got %d", MaxImageBytes, len(data))
+		}
+	case strings.HasPrefix(c.ImageURL, "http://"), strings.HasPrefix(c.ImageURL, "https://"):
+	default:
+		v.fail(field+".image_url", "must be a data url or http(s) url")
+	}
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	output := "ok"
+	for _, tc := range []struct {
+		event  Event
+		fields []string
+	}{
+		{Event{Type: RealtimeClientEventInputAudioBufferAppend, Audio: "AAEC"}, nil},
+		{Event{Type: RealtimeClientEventInputAudioBufferAppend}, []string{"audio"}},
+		{Event{Type: RealtimeClientEventInputAudioBufferAppend, Audio: "%%%"}, []string{"audio"}},
+		{Event{Type: RealtimeClientVideoAppend, VideoFrame: make([]byte, MaxVideoFrameBytes+1)}, []string{"video_frame"}},
+		{Event{Type: RealtimeClientEventSessionUpdate, Session: &Session{Modalities: []Modality{"image"}}}, []string{"session.modalities[0]"}},
+		{Event{Type: RealtimeClientEventConversationItemCreate, Item: &Item{Type: ItemTypeFunctionCallOutput, Output: &output}}, []string{"item.call_id"}},
+		{Event{Type: RealtimeClientEventResponseCreate, Response: &Response{Tools: []Tool{{Type: "retrieval"}}}}, []string{"response.tools[0].name", "response.tools[0].type"}},
+		{Event{Type: RealtimeServerEventResponseTextDelta}, nil},
+		{Event{Type: "client.custom"}, nil},
+	} {
+		err := tc.event.Validate()
+		if len(tc.fields) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.event.Type, err)
+			}
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: expected validation error, got %v", tc.event.Type, err)
+			continue
+		}
+		if len(validationErr.Errors) != len(tc.fields) {
+			t.Errorf("%s: unexpected errors %v", tc.event.Type, validationErr)
+			continue
+		}
+		for i, field := range tc.fields {
+			if validationErr.Errors[i].Field != field {
+				t.Errorf("%s: error %d field = %s, want %s", tc.event.Type, i, validationErr.Errors[i].Field, field)
+			}
+		}
+	}
+}
+
+func TestCheckBase64MatchesDecodeString(t *testing.T) {
+	check := func(s string) {
+		t.Helper()
+		_, want := base64.StdEncoding.DecodeString(s)
+		if got := checkBase64(s); (got == nil) != (want == nil) {
+			t.Errorf("checkBase64(%q) = %v, DecodeString = %v", s, got, want)
+		}
+	}
+	// 换行落在 4KB 分块边界附近会使分组错位；分块末尾的填充之后还有数据应当报错
+	block := strings.Repeat("AAAA", 1<<10)
+	check(block[:4095] + "\n" + "A" + block)
+	check(block[:100] + "\r\n" + block[100:] + "\n")
+	check(block[:4092] + "AA==" + "AAAA")
+	check(block[:4092] + "AAA=" + "\n\n")
+
+	// 短字符串穷举
+	alphabet := []byte("A/=\n*")
+	var walk func(prefix []byte)
+	walk = func(prefix []byte) {
+		check(string(prefix))
+		if len(prefix) == 6 {
+			return
+		}
+		for _, c := range alphabet {
+			walk(append(prefix, c))
+		}
+	}
+	walk(nil)
+}
@@ -0,0 +1,361 @@
+package lifecycle
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Severity 违规的严重程度
+type Severity int
+
+const (
+	// SeverityWarning 可能是服务端实现差异，不一定影响客户端
+	SeverityWarning Severity = iota
+	// SeverityError 违反事件顺序，客户端状态可能因此出错
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Violation 事件流中的一处协议违规
+type Violation struct {
+	Severity   Severity         `json:"severity"`
+	EventType  events.EventType `json:"event_type"`
+	EventID    string           `json:"event_id,omitempty"`
+	ResponseID string           `json:"response_id,omitempty"`
+	ItemID     string           `json:"item_id,omitempty"`
+	Message    string           `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("[%s] %s: %s", v.Severity, v.EventType, v.Message)
+}
+
+type responseState struct {
+	nextOutputIndex int
+}
+
+type partState struct {
+	done bool
+	// implicit 未收到 content_part.added，由第一个增量事件隐式创建
+	implicit bool
+	// 已结束的增量流，key 为事件类型，如 response.text.delta
+	streams map[events.EventType]bool
+}
+
+type itemState struct {
+	responseID       string
+	done             bool
+	nextContentIndex int
+	parts            map[int]*partState
+	argumentsDone    bool
+	// partWarned 已报告过缺少 content_part.added，同一条目只报告一次
+	partWarned bool
+}
+
+// Checker 监听服务端事件流，按状态机检查事件顺序，发现违规时调用 report 并记录。
+// GLM 服务端通常不发送 content_part 事件，缺少 content_part.added 时分片由第一个增量事件隐式创建，每个条目只报告一次警告。
+// 已结束的响应及其条目、已删除的条目不再跟踪。仅用于调试，可直接在 onReceived 回调中调用 Observe
+type Checker struct {
+	mu             sync.Mutex
+	report         func(v Violation)
+	violations     []Violation
+	sessionCreated bool
+	responses      map[string]*responseState
+	items          map[string]*itemState
+	// conversationItems 会话中已创建的条目，包括用户输入的音频条目
+	conversationItems map[string]bool
+	speaking          bool
+}
+
+// NewChecker 创建检查器，report 可以为 nil，之后通过 Violations 获取结果
+func NewChecker(report func(v Violation)) *Checker {
+	return &Checker{
+		report:            report,
+		responses:         make(map[string]*responseState),
+		items:             make(map[string]*itemState),
+		conversationItems: make(map[string]bool),
+	}
+}
+
+// Violations 返回目前发现的所有违规
+func (c *Checker) Violations() []Violation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Violation(nil), c.violations...)
+}
+
+// streamDone 增量事件及其对应的结束事件
+var streamDone = map[events.EventType]events.EventType{
+	events.RealtimeServerEventResponseTextDelta:            events.RealtimeServerEventResponseTextDone,
+	events.RealtimeServerEventResponseAudioDelta:           events.RealtimeServerEventResponseAudioDone,
+	events.RealtimeServerEventResponseAudioTranscriptDelta: events.RealtimeServerEventResponseAudioTranscriptDone,
+}
+
+// Observe 处理一个服务端事件，客户端事件和未知事件会被忽略
+func (c *Checker) Observe(event *events.Event) {
+	if event == nil {
+		return
+	}
+	c.mu.Lock()
+	found := c.check(event)
+	c.violations = append(c.violations, found...)
+	c.mu.Unlock()
+	if c.report != nil {
+		for _, v := range found {
+			c.report(v)
+		}
+	}
+}
+
+func (c *Checker) check(event *events.Event) []Violation {
+	var found []Violation
+	fail := func(severity Severity, format string, args ...any) {
+		found = append(found, Violation{
+			Severity:   severity,
+			EventType:  event.Type,
+			EventID:    event.EventID,
+			ResponseID: responseID(event),
+			ItemID:     itemID(event),
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	switch event.Type {
+	case events.RealtimeServerEventSessionCreated:
+		if c.sessionCreated {
+			fail(SeverityError, "session.created received twice")
+		}
+		c.sessionCreated = true
+		return found
+	case events.RealtimeServerEventError:
+		return found
+	}
+	if !c.sessionCreated {
+		fail(SeverityWarning, "event received before session.created")
+		c.sessionCreated = true
+	}
+
+	switch event.Type {
+	case events.RealtimeServerEventInputAudioBufferSpeechStarted:
+		if c.speaking {
+			fail(SeverityError, "speech_started received again without speech_stopped")
+		}
+		c.speaking = true
+	case events.RealtimeServerEventInputAudioBufferSpeechStopped:
+		if !c.speaking {
+			fail(SeverityWarning, "speech_stopped without speech_started")
+		}
+		c.speaking = false
+	case events.RealtimeServerEventInputAudioBufferCommitted:
+		if event.ItemID != "" {
+			c.conversationItems[event.ItemID] = true
+		}
+	case events.RealtimeServerEventConversationItemCreated:
+		if id := itemID(event); id != "" {
+			c.conversationItems[id] = true
+		}
+	case events.RealtimeServerEventConversationItemDeleted:
+		delete(c.conversationItems, event.ItemID)
+		delete(c.items, event.ItemID)
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionDelta,
+		events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted,
+		events.RealtimeServerEventConversationItemInputAudioTranscriptionFailed:
+		if !c.conversationItems[event.ItemID] {
+			fail(SeverityWarning, "transcription for unknown item_id %q", event.ItemID)
+		}
+
+	case events.RealtimeServerEventResponseCreated:
+		id := responseID(event)
+		if _, ok := c.responses[id]; ok {
+			fail(SeverityError, "response %q created twice", id)
+			break
+		}
+		for other := range c.responses {
+			fail(SeverityWarning, "response %q created while response %q is in progress", id, other)
+		}
+		c.responses[id] = &responseState{}
+	case events.RealtimeServerEventResponseDone:
+		id := responseID(event)
+		if _, ok := c.responses[id]; !ok {
+			fail(SeverityError, "response.done without response.created for %q", id)
+			break
+		}
+		// 已结束的响应及其条目不再需要跟踪，之后的事件会因响应未知被记录
+		delete(c.responses, id)
+		for itemID, item := range c.items {
+			if item.responseID != id {
+				continue
+			}
+			// 缺少 content_part 事件的条目已报告过警告，不再重复报告
+			if !item.done && !item.partWarned {
+				fail(SeverityWarning, "response done before output_item.done for item %q", itemID)
+			}
+			delete(c.items, itemID)
+		}
+
+	case events.RealtimeServerEventResponseOutputItemAdded:
+		resp := c.activeResponse(event, fail)
+		id := itemID(event)
+		if _, ok := c.items[id]; ok {
+			fail(SeverityError, "output item %q added twice", id)
+			break
+		}
+		if resp != nil {
+			if event.OutputIndex != resp.nextOutputIndex {
+				fail(SeverityWarning, "output_index gap: expected %d, got %d", resp.nextOutputIndex, event.OutputIndex)
+			}
+			resp.nextOutputIndex = event.OutputIndex + 1
+		}
+		c.items[id] = &itemState{responseID: responseID(event), parts: make(map[int]*partState)}
+		c.conversationItems[id] = true
+	case events.RealtimeServerEventResponseOutputItemDone:
+		c.activeResponse(event, fail)
+		if item := c.item(event, fail); item != nil {
+			for index, part := range item.parts {
+				if !part.done && !part.implicit {
+					fail(SeverityWarning, "output_item.done before content_part.done for content_index %d", index)
+				}
+			}
+			item.done = true
+		}
+
+	case events.RealtimeServerEventResponseContentPartAdded:
+		c.activeResponse(event, fail)
+		item := c.item(event, fail)
+		if item == nil {
+			break
+		}
+		if _, ok := item.parts[event.ContentIndex]; ok {
+			fail(SeverityError, "content part %d added twice", event.ContentIndex)
+			break
+		}
+		if event.ContentIndex != item.nextContentIndex {
+			fail(SeverityWarning, "content_index gap: expected %d, got %d", item.nextContentIndex, event.ContentIndex)
+		}
+		item.nextContentIndex = event.ContentIndex + 1
+		item.parts[event.ContentIndex] = &partState{streams: make(map[events.EventType]bool)}
+	case events.RealtimeServerEventResponseContentPartDone:
+		c.activeResponse(event, fail)
+		if part := c.part(event, fail); part != nil {
+			if part.done {
+				fail(SeverityError, "content part %d done twice", event.ContentIndex)
+			}
+			part.done = true
+		}
+
+	case events.RealtimeServerEventResponseTextDelta,
+		events.RealtimeServerEventResponseAudioDelta,
+		events.RealtimeServerEventResponseAudioTranscriptDelta:
+		c.activeResponse(event, fail)
+		if part := c.part(event, fail); part != nil && part.streams[event.Type] {
+			fail(SeverityError, "delta after %s", streamDone[event.Type])
+		}
+	case events.RealtimeServerEventResponseTextDone,
+		events.RealtimeServerEventResponseAudioDone,
+		events.RealtimeServerEventResponseAudioTranscriptDone:
+		c.activeResponse(event, fail)
+		if part := c.part(event, fail); part != nil {
+			if part.streams[event.Type] {
+				fail(SeverityError, "%s received twice", event.Type)
+			}
+			part.streams[event.Type] = true
+			for delta, done := range streamDone {
+				if done == event.Type {
+					part.streams[delta] = true
+				}
+			}
+		}
+
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDelta:
+		c.activeResponse(event, fail)
+		if item := c.item(event, fail); item != nil && item.argumentsDone {
+			fail(SeverityError, "function_call_arguments.delta after function_call_arguments.done")
+		}
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDone:
+		c.activeResponse(event, fail)
+		if item := c.item(event, fail); item != nil {
+			if item.argumentsDone {
+				fail(SeverityError, "function_call_arguments.done received twice")
+			}
+			item.argumentsDone = true
+		}
+	}
+	return found
+}
+
+// activeResponse 返回事件所属的进行中的响应，未创建或已结束时记录违规
+func (c *Checker) activeResponse(event *events.Event, fail func(Severity, string, ...any)) *responseState {
+	id := responseID(event)
+	if id == "" {
+		return nil
+	}
+	resp, ok := c.responses[id]
+	if !ok {
+		fail(SeverityError, "event for unknown or finished response_id %q", id)
+		return nil
+	}
+	return resp
+}
+
+// item 返回事件所属的输出条目，未添加时记录违规
+func (c *Checker) item(event *events.Event, fail func(Severity, string, ...any)) *itemState {
+	id := itemID(event)
+	item, ok := c.items[id]
+	if !ok {
+		fail(SeverityError, "event for unknown item_id %q", id)
+		return nil
+	}
+	if item.done && event.Type != events.RealtimeServerEventResponseOutputItemDone {
+		fail(SeverityError, "event after output_item.done for item %q", id)
+	}
+	if item.done && event.Type == events.RealtimeServerEventResponseOutputItemDone {
+		fail(SeverityError, "output_item.done received twice for item %q", id)
+	}
+	return item
+}
+
+// part 返回事件所属的内容分片，未添加时隐式创建，每个条目只记录一次警告
+func (c *Checker) part(event *events.Event, fail func(Severity, string, ...any)) *partState {
+	item := c.item(event, fail)
+	if item == nil {
+		return nil
+	}
+	part, ok := item.parts[event.ContentIndex]
+	if !ok {
+		if !item.partWarned {
+			fail(SeverityWarning, "event for content_index %d without content_part.added", event.ContentIndex)
+			item.partWarned = true
+		}
+		part = &partState{implicit: true, streams: make(map[events.EventType]bool)}
+		item.parts[event.ContentIndex] = part
+		if event.ContentIndex >= item.nextContentIndex {
+			item.nextContentIndex = event.ContentIndex + 1
+		}
+	}
+	if part.done && event.Type != events.RealtimeServerEventResponseContentPartDone {
+		fail(SeverityError, "event after content_part.done for content_index %d", event.ContentIndex)
+	}
+	return part
+}
+
+func responseID(event *events.Event) string {
+	if event.Response != nil && event.Response.ID != "" {
+		return event.Response.ID
+	}
+	return event.ResponseID
+}
+
+func itemID(event *events.Event) string {
+	if event.Item != nil && event.Item.ID != "" {
+		return event.Item.ID
+	}
+	return event.ItemID
+}
@@ -0,0 +1,154 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func session() *events.Event {
+	return &events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "s1"}}
+}
+
+func created(id string) *events.Event {
+	return &events.Event{Type: events.RealtimeServerEventResponseCreated, Response: &events.Response{ID: id}}
+}
+
+func done(id string) *events.Event {
+	return &events.Event{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: id}}
+}
+
+func item(t events.EventType, responseID, itemID string, index int) *events.Event {
+	return &events.Event{Type: t, ResponseID: responseID, ItemID: itemID, OutputIndex: index, ContentIndex: index}
+}
+
+func TestCheckerValidStream(t *testing.T) {
+	c := NewChecker(nil)
+	for _, event := range []*events.Event{
+		session(),
+		{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "u1"},
+		{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "u1"},
+		{Type: events.RealtimeServerEventInputAudioBufferCommitted, ItemID: "u1"},
+		{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u1"},
+		created("r1"),
+		{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "r1", Item: &events.Item{ID: "i1"}},
+		item(events.RealtimeServerEventResponseContentPartAdded, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseAudioTranscriptDelta, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseAudioDelta, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseAudioDone, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseAudioTranscriptDone, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseContentPartDone, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseOutputItemDone, "r1", "i1", 0),
+		{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "r1", OutputIndex: 1, Item: &events.Item{ID: "f1"}},
+		item(events.RealtimeServerEventResponseFunctionCallArgumentsDelta, "r1", "f1", 0),
+		item(events.RealtimeServerEventResponseFunctionCallArgumentsDone, "r1", "f1", 0),
+		{Type: events.RealtimeServerEventResponseOutputItemDone, ResponseID: "r1", OutputIndex: 1, Item: &events.Item{ID: "f1"}},
+		done("r1"),
+	} {
+		c.Observe(event)
+	}
+	if v := c.Violations(); len(v) != 0 {
+		t.Fatalf("unexpected violations: %v", v)
+	}
+}
+
+func TestCheckerViolations(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		events   []*events.Event
+		severity Severity
+		message  string
+	}{
+		{"done without created", []*events.Event{session(), done("r1")}, SeverityError, "without response.created"},
+		{"unknown item", []*events.Event{
+			session(), created("r1"),
+			item(events.RealtimeServerEventResponseTextDelta, "r1", "missing", 0),
+		}, SeverityError, "unknown item_id"},
+		{"content index gap", []*events.Event{
+			session(), created("r1"),
+			{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "r1", ItemID: "i1"},
+			item(events.RealtimeServerEventResponseContentPartAdded, "r1", "i1", 0),
+			item(events.RealtimeServerEventResponseContentPartAdded, "r1", "i1", 2),
+		}, SeverityWarning, "content_index gap: expected 1, got 2"},
+		{"second speech_started", []*events.Event{
+			session(),
+			{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted},
+			{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted},
+		}, SeverityError, "without speech_stopped"},
+		{"delta after done", []*events.Event{
+			session(), created("r1"),
+			{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "r1", ItemID: "i1"},
+			item(events.RealtimeServerEventResponseContentPartAdded, "r1", "i1", 0),
+			item(events.RealtimeServerEventResponseTextDone, "r1", "i1", 0),
+			item(events.RealtimeServerEventResponseTextDelta, "r1", "i1", 0),
+		}, SeverityError, "delta after response.text.done"},
+		{"before session", []*events.Event{created("r1")}, SeverityWarning, "before session.created"},
+	} {
+		var reported []Violation
+		c := NewChecker(func(v Violation) { reported = append(reported, v) })
+		for _, event := range tc.events {
+			c.Observe(event)
+		}
+		if len(reported) != 1 {
+			t.Errorf("%s: expected one violation, got %v", tc.name, reported)
+			continue
+		}
+		if v := reported[0]; v.Severity != tc.severity || !strings.Contains(v.Message, tc.message) {
+			t.Errorf("%s: unexpected violation %s", tc.name, v)
+		}
+	}
+}
+
+func TestCheckerSampleOutputs(t *testing.T) {
+	files, err := filepath.Glob("../samples/files/*.Output")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no sample outputs found: %v", err)
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := NewChecker(nil)
+		for _, line := range strings.Split(string(data), "\n") {
+			if !strings.HasPrefix(line, "{") {
+				continue
+			}
+			event := &events.Event{}
+			if err := json.Unmarshal([]byte(line), event); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			c.Observe(event)
+		}
+		for _, v := range c.Violations() {
+			if v.Severity == SeverityError {
+				t.Errorf("%s: %s", filepath.Base(name), v)
+			}
+		}
+	}
+}
+
+func TestCheckerImplicitPart(t *testing.T) {
+	c := NewChecker(nil)
+	for _, event := range []*events.Event{
+		session(), created("r1"),
+		{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "r1", ItemID: "i1"},
+		item(events.RealtimeServerEventResponseTextDelta, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseTextDelta, "r1", "i1", 0),
+		item(events.RealtimeServerEventResponseTextDone, "r1", "i1", 0),
+		done("r1"),
+	} {
+		c.Observe(event)
+	}
+	v := c.Violations()
+	if len(v) != 1 || v[0].Severity != SeverityWarning || !strings.Contains(v[0].Message, "without content_part.added") {
+		t.Fatalf("expected one content_part warning, got %v", v)
+	}
+	if len(c.responses) != 0 || len(c.items) != 0 {
+		t.Fatalf("finished response not pruned: %d responses, %d items", len(c.responses), len(c.items))
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/toolcall"
+)
+
+// ToEventTool 将 MCP 工具转换为 session.update 中的工具定义
+func ToEventTool(t Tool) (events.Tool, error) {
+	tool := events.Tool{Type: "function", Name: t.Name, Description: t.Description}
+	if len(t.InputSchema) > 0 {
+		if err := json.Unmarshal(t.InputSchema, &tool.Parameters); err != nil {
+			return events.Tool{}, fmt.Errorf("decode input schema of tool %s failed: %w", t.Name, err)
+		}
+	}
+	if tool.Parameters.Type == "" {
+		tool.Parameters.Type = "object"
+	}
+	return tool, nil
+}
+
+// Register 列出 MCP 服务端的工具并注册到 registry，模型发起的调用会以 tools/call 转发给服务端，
+// 结果作为 function_call_output 发回模型。返回注册的工具名
+func Register(ctx context.Context, registry *toolcall.Registry, client *Client) ([]string, error) {
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		tool, err := ToEventTool(t)
+		if err != nil {
+			return names, err
+		}
+		if err := registry.Register(tool, Handler(client, t.Name)); err != nil {
+			return names, err
+		}
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// Handler 返回将调用转发给 MCP 服务端指定工具的处理函数
+func Handler(client *Client, name string) toolcall.Handler {
+	return func(ctx context.Context, arguments string) (string, error) {
+		if strings.TrimSpace(arguments) == "" {
+			arguments = "{}"
+		}
+		result, err := client.CallTool(ctx, name, json.RawMessage(arguments))
+		if err != nil {
+			return "", err
+		}
+		return ResultOutput(result)
+	}
+}
+
+// ResultOutput 将 tools/call 结果转换为 function_call_output：内容全部为文本时拼接文本，
+// 包含图片等其他内容时编码为 JSON；isError 为 true 时以文本内容作为错误返回
+func ResultOutput(result *CallToolResult) (string, error) {
+	texts := make([]string, 0, len(result.Content))
+	textOnly := true
+	for _, c := range result.Content {
+		if c.Type != "text" {
+			textOnly = false
+			continue
+		}
+		texts = append(texts, c.Text)
+	}
+	if result.IsError {
+		message := strings.Join(texts, "\n")
+		if message == "" {
+			message = "tool returned an error"
+		}
+		return "", errors.New(message)
+	}
+	if len(result.Content) == 0 && len(result.StructuredContent) > 0 {
+		return string(result.StructuredContent), nil
+	}
+	if textOnly {
+		return strings.Join(texts, "\n"), nil
+	}
+	data, err := json.Marshal(result.Content)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
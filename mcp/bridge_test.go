@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/toolcall"
+)
+
+// fakeServer 在内存管道上模拟一个 MCP 服务端
+func fakeServer(t *testing.T, r io.Reader, w io.WriteCloser) {
+	defer w.Close()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				Cursor    string          `json:"cursor"`
+				Name      string          `json:"name"`
+				Arguments json.RawMessage `json:"arguments"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			t.Errorf("invalid request: %v", err)
+			return
+		}
+		if len(req.ID) == 0 {
+			continue
+		}
+		var result any
+		switch req.Method {
+		case "initialize":
+			result = map[string]any{"protocolVersion": ProtocolVersion, "capabilities": map[string]any{"tools": map[string]any{}}}
+		case "tools/list":
+			if req.Params.Cursor == "" {
+				result = map[string]any{"tools": []any{map[string]any{
+					"name":        "place_order",
+					"description": "下单",
+					"inputSchema": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"sku": map[string]any{"type": "string"}, "count": map[string]any{"type": "integer", "minimum": 1}},
+						"required":   []string{"sku"},
+					},
+				}}, "nextCursor": "page2"}
+			} else {
+				result = map[string]any{"tools": []any{map[string]any{"name": "fail"}}}
+			}
+		case "tools/call":
+			if req.Params.Name == "fail" {
+				result = map[string]any{"isError": true, "content": []any{map[string]any{"type": "text", "text": "out of stock"}}}
+			} else {
+				result = map[string]any{"content": []any{map[string]any{"type": "text", "text": "ordered " + string(req.Params.Arguments)}}}
+			}
+		}
+		data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	go fakeServer(t, serverReader, serverWriter)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := NewClient(clientReader, clientWriter)
+	defer client.Close()
+	if err := client.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := toolcall.NewRegistry()
+	names, err := Register(ctx, registry, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "place_order,fail" {
+		t.Fatalf("unexpected tools: %v", names)
+	}
+	tool, _ := registry.Tool("place_order")
+	if tool.Parameters.Properties["count"].Minimum == nil || tool.Parameters.Required[0] != "sku" {
+		t.Errorf("schema not mapped: %+v", tool.Parameters)
+	}
+
+	output, err := registry.Invoke(ctx, "place_order", `{"sku":"A1","count":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != `ordered {"sku":"A1","count":2}` {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if _, err := registry.Invoke(ctx, "place_order", `{"count":0}`); err == nil {
+		t.Error("expected validation error")
+	}
+	if _, err := registry.Invoke(ctx, "fail", ``); err == nil || err.Error() != "out of stock" {
+		t.Errorf("expected tool error, got %v", err)
+	}
+}
@@ -0,0 +1,351 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ProtocolVersion 客户端声明的 MCP 协议版本
+const ProtocolVersion = "2024-11-05"
+
+// ClientName 初始化时上报的客户端名称
+const ClientName = "glm-realtime-sdk"
+
+// Tool MCP 服务端声明的工具
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// Content tools/call 结果中的一段内容
+type Content struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Data     string          `json:"data,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// CallToolResult tools/call 的返回结果
+type CallToolResult struct {
+	Content           []Content       `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+// RPCError JSON-RPC 错误
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type incoming struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type reply struct {
+	result json.RawMessage
+	err    error
+}
+
+// Client 通过 stdio 与 MCP 服务端通信的客户端，消息为按行分隔的 JSON-RPC 2.0
+type Client struct {
+	reader io.Reader
+	writer io.WriteCloser
+	cmd    *exec.Cmd
+
+	writeLock sync.Mutex
+	mu        sync.Mutex
+	nextID    int64
+	pending   map[string]chan reply
+	closed    bool
+	done      chan struct{}
+	err       error
+
+	onToolsChanged func()
+}
+
+// Launch 启动本地 MCP 服务端进程并完成初始化，服务端的 stderr 输出到当前进程的 stderr
+func Launch(ctx context.Context, command string, args ...string) (*Client, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start mcp server %s failed: %w", command, err)
+	}
+	c := NewClient(stdout, stdin)
+	c.cmd = cmd
+	if err := c.Initialize(ctx); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient 基于已建立的 stdio 连接创建客户端，调用方需再调用 Initialize
+func NewClient(r io.Reader, w io.WriteCloser) *Client {
+	c := &Client{
+		reader:  r,
+		writer:  w,
+		pending: make(map[string]chan reply),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// OnToolsChanged 设置收到 notifications/tools/list_changed 时的回调
+func (c *Client) OnToolsChanged(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onToolsChanged = fn
+}
+
+// Initialize 发送 initialize 请求和 notifications/initialized 通知
+func (c *Client) Initialize(ctx context.Context) error {
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": ClientName, "version": "1.0.0"},
+	}
+	if _, err := c.call(ctx, "initialize", params); err != nil {
+		return fmt.Errorf("mcp initialize failed: %w", err)
+	}
+	return c.write(&message{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// ListTools 列出服务端的全部工具，自动处理分页
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		result, err := c.call(ctx, "tools/list", params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp tools/list failed: %w", err)
+		}
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(result, &page); err != nil {
+			return nil, fmt.Errorf("decode tools/list result failed: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool 发送 tools/call 请求，arguments 为 JSON 对象，为空时按 {} 处理。
+// ctx 取消时会向服务端发送 notifications/cancelled
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	result, err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments})
+	if err != nil {
+		return nil, err
+	}
+	var res CallToolResult
+	if err := json.Unmarshal(result, &res); err != nil {
+		return nil, fmt.Errorf("decode tools/call result failed: %w", err)
+	}
+	return &res, nil
+}
+
+// Close 关闭连接，由 Launch 启动的进程会在关闭 stdin 后等待退出，超时则强制结束
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+	err := c.writer.Close()
+	if c.cmd == nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- c.cmd.Wait() }()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = c.cmd.Process.Kill()
+		<-exited
+	}
+	return err
+}
+
+// Done 返回一个在连接断开后关闭的 channel
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	c.mu.Lock()
+	if c.closed || c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		if err == nil {
+			err = fmt.Errorf("mcp client closed")
+		}
+		return nil, err
+	}
+	c.nextID++
+	id := json.RawMessage(fmt.Sprintf("%d", c.nextID))
+	ch := make(chan reply, 1)
+	c.pending[string(id)] = ch
+	c.mu.Unlock()
+
+	if err := c.write(&message{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		c.removePending(string(id))
+		return nil, err
+	}
+	select {
+	case r := <-ch:
+		return r.result, r.err
+	case <-ctx.Done():
+		c.removePending(string(id))
+		_ = c.write(&message{
+			JSONRPC: "2.0",
+			Method:  "notifications/cancelled",
+			Params:  map[string]any{"requestId": id, "reason": ctx.Err().Error()},
+		})
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) removePending(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, id)
+}
+
+func (c *Client) write(msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	_, err = c.writer.Write(append(data, '\n'))
+	return err
+}
+
+func (c *Client) readLoop() {
+	scanner := bufio.NewScanner(c.reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var msg incoming
+		if err := json.Unmarshal(line, &msg); err != nil {
+			log.Printf("[MCPClient] Invalid message: %s, err: %v\n", line, err)
+			continue
+		}
+		switch {
+		case msg.Method != "" && len(msg.ID) > 0:
+			c.handleRequest(&msg)
+		case msg.Method != "":
+			c.handleNotification(&msg)
+		default:
+			c.handleResponse(&msg)
+		}
+	}
+	err := scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	c.mu.Lock()
+	c.err = fmt.Errorf("mcp connection closed: %w", err)
+	pending := c.pending
+	c.pending = make(map[string]chan reply)
+	c.mu.Unlock()
+	for _, ch := range pending {
+		ch <- reply{err: c.err}
+	}
+	close(c.done)
+}
+
+func (c *Client) handleResponse(msg *incoming) {
+	c.mu.Lock()
+	ch, ok := c.pending[string(msg.ID)]
+	delete(c.pending, string(msg.ID))
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	if msg.Error != nil {
+		ch <- reply{err: msg.Error}
+		return
+	}
+	ch <- reply{result: msg.Result}
+}
+
+// handleRequest 响应服务端发来的请求，目前仅支持 ping
+func (c *Client) handleRequest(msg *incoming) {
+	resp := &message{JSONRPC: "2.0", ID: msg.ID}
+	if msg.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &RPCError{Code: -32601, Message: "method not found: " + msg.Method}
+	}
+	if err := c.write(resp); err != nil {
+		log.Printf("[MCPClient] Reply %s failed, err: %v\n", msg.Method, err)
+	}
+}
+
+func (c *Client) handleNotification(msg *incoming) {
+	if msg.Method != "notifications/tools/list_changed" {
+		return
+	}
+	c.mu.Lock()
+	fn := c.onToolsChanged
+	c.mu.Unlock()
+	if fn != nil {
+		go fn()
+	}
+}
@@ -0,0 +1,426 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// DefaultURL OpenAI Realtime API 的 websocket 地址
+const DefaultURL = "wss://api.openai.com/v1/realtime?model=gpt-realtime"
+
+// SampleRate OpenAI Realtime API 默认的 PCM 采样率，服务端会话配置中带有采样率时以其为准
+const SampleRate = 24000
+
+// Version OpenAI Realtime API 的协议版本
+type Version int
+
+const (
+	// VersionGA 正式版协议：事件重命名为 response.output_text.delta 等，会话配置使用 audio.input / audio.output 嵌套结构
+	VersionGA Version = iota
+	// VersionBeta 测试版协议，需携带 OpenAI-Beta: realtime=v1 请求头
+	VersionBeta
+)
+
+// serverEventNames OpenAI 服务端事件名到 SDK 事件名的映射，测试版与 SDK 同名的事件无需列出
+var serverEventNames = map[string]events.EventType{
+	"response.output_text.delta":             events.RealtimeServerEventResponseTextDelta,
+	"response.output_text.done":              events.RealtimeServerEventResponseTextDone,
+	"response.output_audio.delta":            events.RealtimeServerEventResponseAudioDelta,
+	"response.output_audio.done":             events.RealtimeServerEventResponseAudioDone,
+	"response.output_audio_transcript.delta": events.RealtimeServerEventResponseAudioTranscriptDelta,
+	"response.output_audio_transcript.done":  events.RealtimeServerEventResponseAudioTranscriptDone,
+	"conversation.item.added":                events.RealtimeServerEventConversationItemCreated,
+}
+
+// Protocol 将 SDK 的 GLM 事件模型转换为 OpenAI Realtime 协议，满足 client.Protocol 接口：
+//
+//	c := client.NewRealtimeClient(openai.DefaultURL, apiKey, onReceived)
+//	c.SetProtocol(openai.NewProtocol(openai.VersionGA))
+type Protocol struct {
+	version Version
+
+	mu sync.Mutex
+	// rates 服务端会话配置中的 PCM 采样率，key 为 input_audio_format 或 output_audio_format
+	rates map[string]int
+}
+
+func NewProtocol(version Version) *Protocol {
+	return &Protocol{version: version}
+}
+
+func (p *Protocol) Header(apiKey string) http.Header {
+	header := make(http.Header)
+	if apiKey != "" {
+		header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
+	if p.version == VersionBeta {
+		header.Set("OpenAI-Beta", "realtime=v1")
+	}
+	return header
+}
+
+// EncodeClientEvent 转换客户端事件。OpenAI 不支持的事件（如视频帧）、音频格式和无法识别的图片会返回错误
+func (p *Protocol) EncodeClientEvent(event *events.Event) ([]byte, error) {
+	if event.Type == events.RealtimeClientVideoAppend {
+		return nil, fmt.Errorf("event %s is not supported by OpenAI Realtime", event.Type)
+	}
+	m, err := toMap(event)
+	if err != nil {
+		return nil, err
+	}
+	// OpenAI 会拒绝未知参数，去掉 GLM 特有和空的字段
+	delete(m, "client_timestamp")
+	delete(m, "beta_fields")
+	if d, _ := m["delta"].(string); d == "" {
+		delete(m, "delta")
+	}
+	if session, ok := m["session"].(map[string]any); ok {
+		if m["session"], err = p.encodeSession(session); err != nil {
+			return nil, err
+		}
+		// 正式版的转写会话通过 session.update 配置，会话类型为 transcription
+		if p.version == VersionGA && event.Type == events.RealtimeClientEventTranscriptionSessionUpdate {
+			m["type"] = string(events.RealtimeClientEventSessionUpdate)
+			session["type"] = "transcription"
+			delete(session, "output_modalities")
+		}
+	}
+	if response, ok := m["response"].(map[string]any); ok {
+		p.encodeResponse(response)
+	}
+	if item, ok := m["item"].(map[string]any); ok {
+		if err = p.encodeItem(item); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(m)
+}
+
+// DecodeServerEvent 将 OpenAI 服务端事件转换为 SDK 事件，同时兼容正式版和测试版的事件名与会话结构
+func (p *Protocol) DecodeServerEvent(data []byte) (*events.Event, error) {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if typ, ok := m["type"].(string); ok {
+		if renamed, ok := serverEventNames[typ]; ok {
+			m["type"] = string(renamed)
+		}
+	}
+	if session, ok := m["session"].(map[string]any); ok {
+		if m["type"] == string(events.RealtimeServerEventSessionUpdated) && session["type"] == "transcription" {
+			m["type"] = string(events.RealtimeServerEventTranscriptionSessionUpdated)
+		}
+		decodeSession(session, p.recordRate)
+	}
+	if response, ok := m["response"].(map[string]any); ok {
+		renameKey(response, "output_modalities", "modalities")
+		renameKey(response, "max_response_output_tokens", "max_output_tokens")
+		if output, ok := response["output"].([]any); ok {
+			for _, item := range output {
+				if item, ok := item.(map[string]any); ok {
+					decodeItem(item)
+				}
+			}
+		}
+	}
+	if item, ok := m["item"].(map[string]any); ok {
+		decodeItem(item)
+	}
+	if part, ok := m["part"].(map[string]any); ok {
+		decodeContent(part)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	event := &events.Event{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (p *Protocol) encodeSession(s map[string]any) (map[string]any, error) {
+	delete(s, "beta_fields")
+	delete(s, "flow_backend")
+	delete(s, "tts_backend")
+	if t, ok := s["input_audio_transcription"].(map[string]any); ok {
+		if enabled, ok := t["enabled"].(bool); ok && !enabled {
+			s["input_audio_transcription"] = nil
+		} else {
+			delete(t, "enabled")
+		}
+	}
+	for _, key := range []string{"input_audio_format", "output_audio_format"} {
+		format, ok := s[key].(string)
+		if !ok {
+			continue
+		}
+		encoded, err := encodeAudioFormat(format, p.version, p.rate(key))
+		if err != nil {
+			return nil, err
+		}
+		s[key] = encoded
+	}
+	if p.version == VersionBeta {
+		return s, nil
+	}
+
+	// 正式版将音频相关配置移入 audio.input / audio.output
+	input, output := map[string]any{}, map[string]any{}
+	moveKey(s, "input_audio_format", input, "format")
+	moveKey(s, "input_audio_transcription", input, "transcription")
+	moveKey(s, "input_audio_noise_reduction", input, "noise_reduction")
+	moveKey(s, "turn_detection", input, "turn_detection")
+	moveKey(s, "output_audio_format", output, "format")
+	moveKey(s, "voice", output, "voice")
+	audio := map[string]any{}
+	if len(input) > 0 {
+		audio["input"] = input
+	}
+	if len(output) > 0 {
+		audio["output"] = output
+	}
+	if len(audio) > 0 {
+		s["audio"] = audio
+	}
+	if modalities, ok := s["modalities"].([]any); ok {
+		s["output_modalities"] = outputModalities(modalities)
+		delete(s, "modalities")
+	}
+	renameKey(s, "max_response_output_tokens", "max_output_tokens")
+	// 正式版不再支持 temperature
+	delete(s, "temperature")
+	s["type"] = "realtime"
+	return s, nil
+}
+
+func (p *Protocol) encodeResponse(r map[string]any) {
+	delete(r, "object")
+	delete(r, "status")
+	if p.version == VersionBeta {
+		renameKey(r, "max_output_tokens", "max_response_output_tokens")
+		return
+	}
+	if modalities, ok := r["modalities"].([]any); ok {
+		r["output_modalities"] = outputModalities(modalities)
+		delete(r, "modalities")
+	}
+	delete(r, "temperature")
+	if voice, ok := r["voice"]; ok {
+		r["audio"] = map[string]any{"output": map[string]any{"voice": voice}}
+		delete(r, "voice")
+	}
+}
+
+// recordRate 记录服务端会话配置中的 PCM 采样率，之后发送的会话配置使用相同的采样率
+func (p *Protocol) recordRate(key string, rate int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rates == nil {
+		p.rates = make(map[string]int)
+	}
+	p.rates[key] = rate
+}
+
+func (p *Protocol) rate(key string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rate, ok := p.rates[key]; ok {
+		return rate
+	}
+	return SampleRate
+}
+
+func (p *Protocol) encodeItem(item map[string]any) error {
+	for _, key := range []string{"id", "object", "status"} {
+		if v, _ := item[key].(string); v == "" {
+			delete(item, key)
+		}
+	}
+	if p.version == VersionBeta {
+		return nil
+	}
+	if content, ok := item["content"].([]any); ok {
+		for _, c := range content {
+			if c, ok := c.(map[string]any); ok {
+				switch c["type"] {
+				case string(events.ContentTypeText):
+					c["type"] = "output_text"
+				case string(events.ContentTypeAudio):
+					c["type"] = "output_audio"
+				case string(events.ContentTypeInputImage):
+					if err := encodeImage(c); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// encodeImage OpenAI 的 input_image 只接受 image_url，原始图片数据转换为 data URL
+func encodeImage(c map[string]any) error {
+	raw, ok := c["image"].(string)
+	if !ok {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return fmt.Errorf("decode input_image failed: %w", err)
+	}
+	mime, err := events.SniffImageType(data)
+	if err != nil {
+		return err
+	}
+	delete(c, "image")
+	c["image_url"] = "data:" + mime + ";base64," + raw
+	return nil
+}
+
+// decodeSession 转换服务端会话配置，正式版音频格式中的 PCM 采样率交给 record
+func decodeSession(s map[string]any, record func(key string, rate int)) {
+	renameKey(s, "output_modalities", "modalities")
+	renameKey(s, "max_output_tokens", "max_response_output_tokens")
+	if audio, ok := s["audio"].(map[string]any); ok {
+		if input, ok := audio["input"].(map[string]any); ok {
+			moveKey(input, "format", s, "input_audio_format")
+			moveKey(input, "transcription", s, "input_audio_transcription")
+			moveKey(input, "noise_reduction", s, "input_audio_noise_reduction")
+			moveKey(input, "turn_detection", s, "turn_detection")
+		}
+		if output, ok := audio["output"].(map[string]any); ok {
+			moveKey(output, "format", s, "output_audio_format")
+			moveKey(output, "voice", s, "voice")
+		}
+		delete(s, "audio")
+	}
+	for _, key := range []string{"input_audio_format", "output_audio_format"} {
+		format, ok := s[key]
+		if !ok {
+			continue
+		}
+		if m, ok := format.(map[string]any); ok && m["type"] == "audio/pcm" {
+			if rate, ok := m["rate"].(float64); ok && rate > 0 {
+				record(key, int(rate))
+			}
+		}
+		s[key] = DecodeAudioFormat(format)
+	}
+	if t, ok := s["input_audio_transcription"].(map[string]any); ok {
+		t["enabled"] = true
+	}
+	if typ, _ := s["type"].(string); typ == "realtime" || typ == "transcription" {
+		delete(s, "type")
+	}
+}
+
+func decodeItem(item map[string]any) {
+	if content, ok := item["content"].([]any); ok {
+		for _, c := range content {
+			if c, ok := c.(map[string]any); ok {
+				decodeContent(c)
+			}
+		}
+	}
+}
+
+func decodeContent(c map[string]any) {
+	switch c["type"] {
+	case "output_text":
+		c["type"] = string(events.ContentTypeText)
+	case "output_audio":
+		c["type"] = string(events.ContentTypeAudio)
+	}
+}
+
+// EncodeAudioFormat 将 GLM 音频格式名转换为 OpenAI 的格式：正式版为 {"type": "audio/pcm", "rate": 24000} 形式，
+// 测试版为 pcm16、g711_ulaw、g711_alaw。OpenAI 不支持 wav、mp3 等封装格式
+func EncodeAudioFormat(format string, version Version) (any, error) {
+	return encodeAudioFormat(format, version, SampleRate)
+}
+
+// encodeAudioFormat 同 EncodeAudioFormat，正式版的 PCM 使用指定的采样率，测试版的 pcm16 固定为 24kHz
+func encodeAudioFormat(format string, version Version, rate int) (any, error) {
+	var beta, ga string
+	switch strings.ToLower(format) {
+	case "pcm", "pcm16", "audio/pcm":
+		beta, ga = "pcm16", "audio/pcm"
+	case "g711_ulaw", "pcmu", "audio/pcmu":
+		beta, ga = "g711_ulaw", "audio/pcmu"
+	case "g711_alaw", "pcma", "audio/pcma":
+		beta, ga = "g711_alaw", "audio/pcma"
+	default:
+		return nil, fmt.Errorf("audio format %s is not supported by OpenAI Realtime", format)
+	}
+	if version == VersionBeta {
+		return beta, nil
+	}
+	if ga == "audio/pcm" {
+		return map[string]any{"type": ga, "rate": rate}, nil
+	}
+	return map[string]any{"type": ga}, nil
+}
+
+// DecodeAudioFormat 将 OpenAI 的音频格式（字符串或正式版的对象形式）转换为 GLM 的格式名
+func DecodeAudioFormat(format any) string {
+	name, ok := format.(string)
+	if m, isMap := format.(map[string]any); isMap {
+		name, ok = m["type"].(string)
+	}
+	if !ok {
+		return ""
+	}
+	switch name {
+	case "pcm16", "audio/pcm":
+		return "pcm"
+	case "audio/pcmu":
+		return "g711_ulaw"
+	case "audio/pcma":
+		return "g711_alaw"
+	}
+	return name
+}
+
+// outputModalities 正式版只接受 ["text"] 或 ["audio"]，音频输出本身包含转写文本
+func outputModalities(modalities []any) []any {
+	for _, m := range modalities {
+		if m == string(events.ModalityAudio) {
+			return []any{string(events.ModalityAudio)}
+		}
+	}
+	return []any{string(events.ModalityText)}
+}
+
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func renameKey(m map[string]any, from, to string) {
+	moveKey(m, from, m, to)
+}
+
+func moveKey(src map[string]any, from string, dst map[string]any, to string) {
+	v, ok := src[from]
+	if !ok {
+		return
+	}
+	delete(src, from)
+	dst[to] = v
+}
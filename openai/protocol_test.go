@@ -0,0 +1,207 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func encode(t *testing.T, p *Protocol, event *events.Event) map[string]any {
+	t.Helper()
+	data, err := p.EncodeClientEvent(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEncodeSessionUpdate(t *testing.T) {
+	event := &events.Event{
+		Type:            events.RealtimeClientEventSessionUpdate,
+		ClientTimestamp: 1,
+		Session: &events.Session{
+			Modalities:              []events.Modality{events.ModalityText, events.ModalityAudio},
+			Voice:                   "alloy",
+			InputAudioFormat:        "pcm",
+			OutputAudioFormat:       "pcm",
+			InputAudioTranscription: events.Some(events.InputAudioTranscription{Enabled: true, Model: "whisper-1"}),
+			TurnDetection:           events.Null[events.TurnDetection](),
+			Temperature:             events.Some(0.8),
+			BetaFields:              &events.BetaFields{ChatMode: events.ChatModeAudio},
+		},
+	}
+
+	ga := encode(t, NewProtocol(VersionGA), event)
+	want := map[string]any{
+		"type": "session.update",
+		"session": map[string]any{
+			"type":              "realtime",
+			"output_modalities": []any{"audio"},
+			"audio": map[string]any{
+				"input": map[string]any{
+					"format":         map[string]any{"type": "audio/pcm", "rate": float64(SampleRate)},
+					"transcription":  map[string]any{"model": "whisper-1"},
+					"turn_detection": nil,
+				},
+				"output": map[string]any{
+					"format": map[string]any{"type": "audio/pcm", "rate": float64(SampleRate)},
+					"voice":  "alloy",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(ga, want) {
+		t.Errorf("GA session.update mismatch:\n got %v\nwant %v", ga, want)
+	}
+
+	beta := encode(t, NewProtocol(VersionBeta), event)
+	session := beta["session"].(map[string]any)
+	if session["input_audio_format"] != "pcm16" || session["temperature"] != 0.8 {
+		t.Errorf("unexpected beta session: %v", session)
+	}
+	if _, ok := session["beta_fields"]; ok {
+		t.Error("beta_fields should be removed")
+	}
+
+	event.Session.InputAudioFormat = "wav"
+	if _, err := NewProtocol(VersionGA).EncodeClientEvent(event); err == nil {
+		t.Error("wav should be rejected")
+	}
+}
+
+func TestEncodeItemAndResponse(t *testing.T) {
+	text := "hello"
+	item := encode(t, NewProtocol(VersionGA), &events.Event{
+		Type: events.RealtimeClientEventConversationItemCreate,
+		Item: &events.Item{
+			Type:    events.ItemTypeMessage,
+			Role:    events.ItemRoleAssistant,
+			Content: []events.Content{{Type: events.ContentTypeText, Text: &text}},
+		},
+	})["item"].(map[string]any)
+	if item["content"].([]any)[0].(map[string]any)["type"] != "output_text" {
+		t.Errorf("content type should be output_text, got %v", item)
+	}
+	if _, ok := item["id"]; ok {
+		t.Error("empty id should be removed")
+	}
+
+	response := encode(t, NewProtocol(VersionBeta), &events.Event{
+		Type:     events.RealtimeClientEventResponseCreate,
+		Response: &events.Response{MaxOutputTokens: events.Some(100)},
+	})["response"].(map[string]any)
+	if response["max_response_output_tokens"] != float64(100) {
+		t.Errorf("max_output_tokens should be renamed, got %v", response)
+	}
+
+	if _, err := NewProtocol(VersionGA).EncodeClientEvent(&events.Event{Type: events.RealtimeClientVideoAppend}); err == nil {
+		t.Error("video frames should be rejected")
+	}
+
+	// 无法识别的图片返回错误，不能静默丢弃
+	if _, err := NewProtocol(VersionGA).EncodeClientEvent(&events.Event{
+		Type: events.RealtimeClientEventConversationItemCreate,
+		Item: &events.Item{
+			Type:    events.ItemTypeMessage,
+			Role:    events.ItemRoleUser,
+			Content: []events.Content{{Type: events.ContentTypeInputImage, Image: []byte("not an image")}},
+		},
+	}); err == nil {
+		t.Error("unrecognized image should be rejected")
+	}
+}
+
+func TestPCMRateFromSession(t *testing.T) {
+	p := NewProtocol(VersionGA)
+	if _, err := p.DecodeServerEvent([]byte(`{"type":"session.created","session":{"type":"realtime",` +
+		`"audio":{"input":{"format":{"type":"audio/pcm","rate":16000}}}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	m := encode(t, p, &events.Event{
+		Type:    events.RealtimeClientEventSessionUpdate,
+		Session: &events.Session{InputAudioFormat: "pcm", OutputAudioFormat: "pcm"},
+	})
+	audio := m["session"].(map[string]any)["audio"].(map[string]any)
+	if rate := audio["input"].(map[string]any)["format"].(map[string]any)["rate"]; rate != float64(16000) {
+		t.Errorf("input rate should follow the server session, got %v", rate)
+	}
+	if rate := audio["output"].(map[string]any)["format"].(map[string]any)["rate"]; rate != float64(SampleRate) {
+		t.Errorf("output rate should default to %d, got %v", SampleRate, rate)
+	}
+}
+
+func TestDecodeServerEvent(t *testing.T) {
+	p := NewProtocol(VersionGA)
+	event, err := p.DecodeServerEvent([]byte(`{"type":"response.output_audio_transcript.delta","response_id":"r1","delta":"hi"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.RealtimeServerEventResponseAudioTranscriptDelta || event.Delta != "hi" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	event, err = p.DecodeServerEvent([]byte(`{"type":"session.created","session":{"type":"realtime","id":"s1","output_modalities":["audio"],` +
+		`"audio":{"input":{"format":{"type":"audio/pcm","rate":24000},"transcription":{"model":"whisper-1"},"turn_detection":null},` +
+		`"output":{"format":{"type":"audio/pcm","rate":24000},"voice":"alloy"}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := event.Session
+	if s.ID != "s1" || s.Voice != "alloy" || s.InputAudioFormat != "pcm" || s.OutputAudioFormat != "pcm" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if !reflect.DeepEqual(s.Modalities, []events.Modality{events.ModalityAudio}) {
+		t.Errorf("unexpected modalities: %v", s.Modalities)
+	}
+	if tr, ok := s.InputAudioTranscription.Get(); !ok || !tr.Enabled || tr.Model != "whisper-1" {
+		t.Errorf("unexpected transcription: %v", s.InputAudioTranscription)
+	}
+	if !s.TurnDetection.IsNull() {
+		t.Error("turn_detection should be null")
+	}
+	if len(s.Extra) != 0 {
+		t.Errorf("unexpected extra fields: %v", s.Extra)
+	}
+
+	event, err = p.DecodeServerEvent([]byte(`{"type":"conversation.item.added","item":{"id":"i1","type":"message","role":"assistant",` +
+		`"content":[{"type":"output_audio","transcript":"hi"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.RealtimeServerEventConversationItemCreated || event.Item.Content[0].Type != events.ContentTypeAudio {
+		t.Errorf("unexpected item event: %+v", event)
+	}
+}
+
+func TestTranscriptionSession(t *testing.T) {
+	p := NewProtocol(VersionGA)
+	m := encode(t, p, &events.Event{
+		Type: events.RealtimeClientEventTranscriptionSessionUpdate,
+		Session: &events.Session{
+			InputAudioFormat:        "pcm",
+			InputAudioTranscription: events.Some(events.InputAudioTranscription{Enabled: true, Model: "gpt-4o-transcribe", Language: "zh"}),
+		},
+	})
+	session := m["session"].(map[string]any)
+	if m["type"] != "session.update" || session["type"] != "transcription" {
+		t.Fatalf("unexpected transcription session update: %v", m)
+	}
+	transcription := session["audio"].(map[string]any)["input"].(map[string]any)["transcription"]
+	if !reflect.DeepEqual(transcription, map[string]any{"model": "gpt-4o-transcribe", "language": "zh"}) {
+		t.Errorf("unexpected transcription config: %v", transcription)
+	}
+
+	event, err := p.DecodeServerEvent([]byte(`{"type":"session.updated","session":{"type":"transcription","audio":{"input":{"transcription":{"model":"gpt-4o-transcribe"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.RealtimeServerEventTranscriptionSessionUpdated || !event.Session.InputAudioTranscription.IsSet() {
+		t.Errorf("unexpected event: %s", event.ToJson())
+	}
+}
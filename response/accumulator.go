@@ -0,0 +1,220 @@
+package response
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// FunctionCall 响应中的一次函数调用
+type FunctionCall struct {
+	ItemID    string `json:"item_id,omitempty"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Response 一次响应累积的结果
+type Response struct {
+	ID         string                `json:"id"`
+	Status     events.ResponseStatus `json:"status,omitempty"`
+	Text       string                `json:"text,omitempty"`
+	Transcript string                `json:"transcript,omitempty"`
+	// AudioBytes 收到的输出音频字节数，音频数据本身不做保存
+	AudioBytes    int                    `json:"audio_bytes,omitempty"`
+	FunctionCalls []FunctionCall         `json:"function_calls,omitempty"`
+	Searches      []events.BrowserSearch `json:"searches,omitempty"`
+	// Citations 按编号去重后的引用来源，用于渲染脚注
+	Citations []events.Citation `json:"citations,omitempty"`
+	Usage     *events.Usage     `json:"usage,omitempty"`
+}
+
+// Content 返回响应的文本内容，纯文本模式为 Text，语音模式为 Transcript
+func (r *Response) Content() string {
+	if r.Text != "" {
+		return r.Text
+	}
+	return r.Transcript
+}
+
+// Footnotes 将引用来源渲染为脚注，每行形如 "[1] 标题 URL"，没有引用时返回空字符串
+func (r *Response) Footnotes() string {
+	var b strings.Builder
+	for _, c := range r.Citations {
+		line := strings.TrimSpace(strings.Join([]string{c.Title, c.URL}, " "))
+		fmt.Fprintf(&b, "[%d] %s\n", c.Index, line)
+	}
+	return b.String()
+}
+
+func (r *Response) addSearch(search *events.BrowserSearch) {
+	r.Searches = append(r.Searches, *search)
+	for _, c := range search.Citations {
+		merged := false
+		for i := range r.Citations {
+			if r.Citations[i].Index == c.Index {
+				if r.Citations[i].Title == "" {
+					r.Citations[i].Title = c.Title
+				}
+				if r.Citations[i].URL == "" {
+					r.Citations[i].URL = c.URL
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			r.Citations = append(r.Citations, c)
+		}
+	}
+}
+
+func (r *Response) clone() *Response {
+	c := *r
+	c.FunctionCalls = append([]FunctionCall(nil), r.FunctionCalls...)
+	c.Searches = append([]events.BrowserSearch(nil), r.Searches...)
+	c.Citations = append([]events.Citation(nil), r.Citations...)
+	return &c
+}
+
+// Accumulator 按响应累积文本、转写、函数调用、联网搜索结果和用量，
+// 响应结束时通过 onDone 回调完整结果
+type Accumulator struct {
+	mu        sync.Mutex
+	responses map[string]*Response
+	current   string
+	last      *Response
+	// pending 在 response.created 之前收到的搜索结果，挂到下一个响应上
+	pending []*events.BrowserSearch
+	onDone  func(resp *Response)
+}
+
+// NewAccumulator 创建响应累积器，onDone 在每个响应结束时调用，可以为 nil
+func NewAccumulator(onDone func(resp *Response)) *Accumulator {
+	return &Accumulator{
+		responses: make(map[string]*Response),
+		onDone:    onDone,
+	}
+}
+
+// Observe 处理一个服务端事件
+func (a *Accumulator) Observe(event *events.Event) {
+	if event == nil {
+		return
+	}
+	var done *Response
+	a.mu.Lock()
+	switch event.Type {
+	case events.RealtimeServerEventResponseCreated:
+		resp := a.responseLocked(responseID(event))
+		a.current = resp.ID
+		for _, search := range a.pending {
+			resp.addSearch(search)
+		}
+		a.pending = nil
+	case events.RealtimeServerEventResponseTextDelta:
+		a.responseLocked(responseID(event)).Text += event.Delta
+	case events.RealtimeServerEventResponseTextDone:
+		if event.Text != nil {
+			a.responseLocked(responseID(event)).Text = *event.Text
+		}
+	case events.RealtimeServerEventResponseAudioTranscriptDelta:
+		a.responseLocked(responseID(event)).Transcript += event.Delta
+	case events.RealtimeServerEventResponseAudioTranscriptDone:
+		if event.Transcript != nil {
+			a.responseLocked(responseID(event)).Transcript = *event.Transcript
+		}
+	case events.RealtimeServerEventResponseAudioDelta:
+		if data, err := base64.StdEncoding.DecodeString(event.Delta); err == nil {
+			a.responseLocked(responseID(event)).AudioBytes += len(data)
+		}
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDone:
+		resp := a.responseLocked(responseID(event))
+		resp.FunctionCalls = append(resp.FunctionCalls, FunctionCall{
+			ItemID:    event.ItemID,
+			CallID:    event.CallID,
+			Name:      event.Name,
+			Arguments: event.Arguments,
+		})
+	case events.RealtimeServerResponseFunctionCallSimpleBrowserEvent,
+		events.RealtimeServerResponseFunctionCallSimpleBrowserResultEvent:
+		search, err := events.ParseSimpleBrowser(event)
+		if err != nil {
+			log.Printf("[ResponseAccumulator] Parse simple_browser failed, err: %v\n", err)
+			break
+		}
+		if search == nil {
+			break
+		}
+		id := search.ResponseID
+		if id == "" {
+			id = a.current
+		}
+		if id == "" {
+			a.pending = append(a.pending, search)
+			break
+		}
+		a.responseLocked(id).addSearch(search)
+	case events.RealtimeServerEventResponseDone:
+		resp := a.responseLocked(responseID(event))
+		if event.Response != nil {
+			resp.Status = event.Response.Status
+			resp.Usage = event.Response.Usage
+		}
+		delete(a.responses, resp.ID)
+		if a.current == resp.ID {
+			a.current = ""
+		}
+		a.last = resp
+		done = resp.clone()
+	}
+	a.mu.Unlock()
+	if done != nil && a.onDone != nil {
+		a.onDone(done)
+	}
+}
+
+// Response 返回进行中的响应的当前状态
+func (a *Accumulator) Response(id string) (*Response, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	resp, ok := a.responses[id]
+	if !ok {
+		return nil, false
+	}
+	return resp.clone(), true
+}
+
+// Last 返回最近一个已结束的响应，尚无时返回 nil
+func (a *Accumulator) Last() *Response {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.last == nil {
+		return nil
+	}
+	return a.last.clone()
+}
+
+// responseLocked 取出或创建响应，事件没有 response_id 时归入当前响应
+func (a *Accumulator) responseLocked(id string) *Response {
+	if id == "" {
+		id = a.current
+	}
+	resp, ok := a.responses[id]
+	if !ok {
+		resp = &Response{ID: id, Status: events.ResponseStatusInProgress}
+		a.responses[id] = resp
+	}
+	return resp
+}
+
+func responseID(event *events.Event) string {
+	if event.Response != nil && event.Response.ID != "" {
+		return event.Response.ID
+	}
+	return event.ResponseID
+}
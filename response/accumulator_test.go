@@ -0,0 +1,61 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func TestAccumulatorCitations(t *testing.T) {
+	var done *Response
+	a := NewAccumulator(func(resp *Response) { done = resp })
+
+	searchMeta := `{"query":"北京天气","search_result":[` +
+		`{"refer":"ref_1","title":"北京天气预报","link":"https://weather.example.com/beijing","content":"今天晴，最高 25 度"},` +
+		`{"refer":"ref_2","title":"中国气象局","link":"https://cma.example.com","media":"气象局"}]}`
+	for _, event := range []*events.Event{
+		// 搜索结果先于 response.created 到达
+		{Type: events.RealtimeServerResponseFunctionCallSimpleBrowserEvent, BetaFields: &events.BetaFields{
+			SimpleBrowser: &events.SimpleBrowser{Description: "北京天气", SearchMeta: searchMeta},
+		}},
+		{Type: events.RealtimeServerEventResponseCreated, Response: &events.Response{ID: "resp_1"}},
+		{Type: events.RealtimeServerEventResponseTextDelta, ResponseID: "resp_1", Delta: "北京今天晴【1†source】"},
+		{Type: events.RealtimeServerResponseFunctionCallSimpleBrowserResultEvent, Session: &events.Session{BetaFields: &events.BetaFields{
+			SimpleBrowser: &events.SimpleBrowser{SearchMeta: searchMeta, TextCitation: "北京今天晴【1†source】，数据来自气象局[ref_2]【1】"},
+		}}},
+		{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_1", Status: events.ResponseStatusCompleted}},
+	} {
+		a.Observe(event)
+	}
+
+	if done == nil {
+		t.Fatal("onDone not called")
+	}
+	if done.Text != "北京今天晴【1†source】" || done.Status != events.ResponseStatusCompleted {
+		t.Fatalf("unexpected response: %+v", done)
+	}
+	if len(done.Searches) != 2 {
+		t.Fatalf("expected 2 searches, got %d", len(done.Searches))
+	}
+	result := done.Searches[0].Result
+	if result.Query != "北京天气" || len(result.Sources) != 2 {
+		t.Fatalf("unexpected search result: %+v", result)
+	}
+	source := result.Sources[1]
+	if source.Index != 2 || source.URL != "https://cma.example.com" || source.Site != "气象局" {
+		t.Errorf("unexpected source: %+v", source)
+	}
+	if result.Sources[0].Snippet != "今天晴，最高 25 度" {
+		t.Errorf("unexpected snippet: %q", result.Sources[0].Snippet)
+	}
+	if len(done.Citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", done.Citations)
+	}
+	want := "[1] 北京天气预报 https://weather.example.com/beijing\n[2] 中国气象局 https://cma.example.com\n"
+	if got := done.Footnotes(); got != want {
+		t.Errorf("footnotes = %q, want %q", got, want)
+	}
+	if a.Last() == nil || a.Last().ID != "resp_1" {
+		t.Errorf("Last() = %+v", a.Last())
+	}
+}
@@ -2,18 +2,19 @@ package samples
 
 import (
 	"bufio"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"log"
 	"os"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/MetaGLM/glm-realtime-sdk/golang/client"
 	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+	"github.com/MetaGLM/glm-realtime-sdk/golang/toolcall"
 	"github.com/MetaGLM/glm-realtime-sdk/golang/tools"
 )
 
@@ -48,21 +49,16 @@ func doTestRealtimeClient(inputFilePath, outputFilePath string) {
 	}
 	defer file.Close()
 
-	wavBytes, written := make([][]byte, 0), false
+	wavOut := &wavOutput{path: outputFilePath + ".wav"}
+	defer wavOut.close()
 	var realtimeClient client.RealtimeClient
 	onReceived := func(event *events.Event) error {
 		if event.Type == events.RealtimeServerEventResponseAudioDelta {
-			bytes, err := base64.StdEncoding.DecodeString(event.Delta)
-			if err != nil || len(bytes) == 0 {
-				log.Fatalf("Error decoding audio: %v\n", err)
+			if err := wavOut.write(event.Delta); err != nil {
+				log.Fatalf("Error writing audio: %v\n", err)
 				return err
 			}
-			bytes, err = tools.Pcm2Wav(bytes, 24000, 1, 16)
-			if err != nil || len(bytes) == 0 {
-				log.Fatalf("Error converting pcm to wav: %v\n", err)
-				return err
-			}
-			wavBytes, event.Delta = append(wavBytes, bytes), "Ignored for logging"
+			event.Delta = "Ignored for logging"
 		} else if event.Type == events.RealtimeServerEventSessionUpdated && event.Session != nil && event.Session.BetaFields != nil && event.Session.BetaFields.TTSCloned != nil {
 			event.Session.BetaFields.TTSCloned.Audio = "Ignored for logging"
 		}
@@ -75,9 +71,7 @@ func doTestRealtimeClient(inputFilePath, outputFilePath string) {
 		if event.Type == events.RealtimeServerEventResponseDone || event.Type == events.RealtimeServerEventError {
 			log.Printf("Received event: %s, exiting...\n", event.Type)
 			_ = realtimeClient.Disconnect()
-			if bytes, err := tools.ConcatWavBytes(wavBytes); err == nil && len(bytes) > 0 {
-				_, written = os.WriteFile(outputFilePath+".wav", bytes, 0644), true
-			}
+			wavOut.close()
 		}
 		return nil
 	}
@@ -128,12 +122,51 @@ func doTestRealtimeClient(inputFilePath, outputFilePath string) {
 	}
 
 	realtimeClient.Wait()
+}
 
-	if !written {
-		if bytes, err := tools.ConcatWavBytes(wavBytes); err == nil && len(bytes) > 0 {
-			_, written = os.WriteFile(outputFilePath+".wav", bytes, 0644), true
+// wavOutput 将 response.audio.delta 增量写入 WAV 文件，收到第一段音频时创建文件
+type wavOutput struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	writer *tools.WavWriter
+	closed bool
+}
+
+func (o *wavOutput) write(delta string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return nil
+	}
+	if o.file == nil {
+		file, err := os.Create(o.path)
+		if err != nil {
+			return err
+		}
+		writer, err := tools.NewWavWriter(file, 24000, 1, 16)
+		if err != nil {
+			_ = file.Close()
+			return err
 		}
+		o.file, o.writer = file, writer
+	}
+	return o.writer.WriteBase64(delta)
+}
+
+// close 补写文件头并关闭文件，可重复调用
+func (o *wavOutput) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.closed = true
+	if o.file == nil {
+		return
+	}
+	if err := o.writer.Close(); err != nil {
+		log.Printf("Error finalizing wav: %v\n", err)
 	}
+	_ = o.file.Close()
+	o.file = nil
 }
 
 func doTestRealtimeClientWithFC(inputFilePath, outputFilePath string) {
@@ -153,22 +186,16 @@ func doTestRealtimeClientWithFC(inputFilePath, outputFilePath string) {
 	}
 	defer file.Close()
 
-	wavBytes, callId := make([][]byte, 0), ""
-	var status atomic.Uint32
+	wavOut := &wavOutput{path: outputFilePath + ".wav"}
+	defer wavOut.close()
 	var realtimeClient client.RealtimeClient
 	onReceived := func(event *events.Event) error {
 		if event.Type == events.RealtimeServerEventResponseAudioDelta {
-			bytes, err := base64.StdEncoding.DecodeString(event.Delta)
-			if err != nil || len(bytes) == 0 {
-				log.Fatalf("Error decoding audio: %v\n", err)
+			if err := wavOut.write(event.Delta); err != nil {
+				log.Fatalf("Error writing audio: %v\n", err)
 				return err
 			}
-			bytes, err = tools.Pcm2Wav(bytes, 24000, 1, 16)
-			if err != nil || len(bytes) == 0 {
-				log.Fatalf("Error converting pcm to wav: %v\n", err)
-				return err
-			}
-			wavBytes, event.Delta = append(wavBytes, bytes), "Ignored for logging"
+			event.Delta = "Ignored for logging"
 		}
 		s := event.ToJson()
 		log.Printf("Received message: %s\n\n", s)
@@ -176,22 +203,16 @@ func doTestRealtimeClientWithFC(inputFilePath, outputFilePath string) {
 			log.Fatalf("Error writing to file: %v\n", err)
 			return err
 		}
-		if event.Type == events.RealtimeServerEventResponseFunctionCallArgumentsDone {
-			callId = event.CallID
-		}
-		if event.Type == events.RealtimeServerEventResponseFunctionCallArgumentsDone || event.Type == events.RealtimeServerEventResponseDone {
-			status.Add(1)
-		}
-		if status.Load() > 2 && event.Type == events.RealtimeServerEventResponseDone || event.Type == events.RealtimeServerEventError {
+		// 包含函数调用的响应结束后，注册表会自动发回工具结果并触发新的响应，等待最终的回答
+		if event.Type == events.RealtimeServerEventResponseDone && !hasFunctionCall(event.Response) || event.Type == events.RealtimeServerEventError {
 			log.Printf("Received event: %s, exiting...\n", event.Type)
 			_ = realtimeClient.Disconnect()
-			if bytes, err := tools.ConcatWavBytes(wavBytes); err == nil && len(bytes) > 0 {
-				_ = os.WriteFile(outputFilePath+".wav", bytes, 0644)
-			}
+			wavOut.close()
 		}
 		return nil
 	}
 	realtimeClient = client.NewRealtimeClient(ZHIPU_REALTIME_URL, ZHIPU_API_KEY, onReceived)
+	realtimeClient.SetToolRegistry(newWeatherRegistry(), true)
 
 	if err = realtimeClient.Connect(); err != nil {
 		log.Fatalf("Connect failed, error: %v\n", err)
@@ -218,11 +239,9 @@ func doTestRealtimeClientWithFC(inputFilePath, outputFilePath string) {
 			log.Fatalf("Error unmarshalling event: %v\n", err)
 			return
 		}
-		for event.Type == events.RealtimeClientEventConversationItemCreate && status.Load() < 2 {
-			time.Sleep(time.Second)
-		}
-		if event.Type == events.RealtimeClientEventConversationItemCreate && event.Item != nil {
-			event.Item.CallId = callId
+		// 录制文件中的函数结果及其后的 response.create 由注册表自动完成
+		if event.Type == events.RealtimeClientEventConversationItemCreate {
+			break
 		}
 		if err = realtimeClient.Send(event); err != nil {
 			_ = realtimeClient.Disconnect()
@@ -244,6 +263,32 @@ func doTestRealtimeClientWithFC(inputFilePath, outputFilePath string) {
 	realtimeClient.Wait()
 }
 
+type weatherArgs struct {
+	Location string `json:"location" description:"要查询天气的城市"`
+	Date     string `json:"date,omitempty" description:"要查询天气的日期"`
+}
+
+func newWeatherRegistry() *toolcall.Registry {
+	registry := toolcall.NewRegistry()
+	_ = toolcall.RegisterTyped(registry, "SearchWeather", "查询指定城市的天气", func(ctx context.Context, args weatherArgs) (string, error) {
+		log.Printf("SearchWeather called, location: %s, date: %s\n", args.Location, args.Date)
+		return "最低25°C 最高35°C 骄阳似火", nil
+	})
+	return registry
+}
+
+func hasFunctionCall(response *events.Response) bool {
+	if response == nil {
+		return false
+	}
+	for _, item := range response.Output {
+		if item.Type == events.ItemTypeFunctionCall {
+			return true
+		}
+	}
+	return false
+}
+
 func doTestRealtimeClientWithVLM(inputFilePath, outputFilePath string) {
 
 	dir, err := os.Getwd()
@@ -0,0 +1,295 @@
+package toolcall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// DefaultTimeout 工具调用的默认超时时间
+const DefaultTimeout = 30 * time.Second
+
+// ErrCancelled 工具调用因 response.cancel、用户打断或连接断开而被取消
+var ErrCancelled = errors.New("tool call cancelled")
+
+// Call 一次函数调用
+type Call struct {
+	ResponseID  string
+	ItemID      string
+	CallID      string
+	Name        string
+	Arguments   string
+	OutputIndex int
+}
+
+type pendingCall struct {
+	call     *Call
+	output   string
+	finished bool
+}
+
+type pendingResponse struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	calls     []*pendingCall
+	done      bool
+	cancelled bool
+}
+
+// Dispatcher 监听 response.function_call_arguments.done，并发调用注册表中的工具。
+// 同一响应中的所有调用完成且响应结束后，按 output_index 顺序发回 function_call_output，
+// 再触发一次 response.create。响应被取消、用户打断或连接断开时，进行中的调用会被取消
+type Dispatcher struct {
+	registry *Registry
+	sender   events.Sender
+
+	mu           sync.Mutex
+	autoResponse bool
+	timeout      time.Duration
+	sessionID    string
+	responses    map[string]*pendingResponse
+}
+
+func NewDispatcher(registry *Registry, sender events.Sender) *Dispatcher {
+	return &Dispatcher{
+		registry:     registry,
+		sender:       sender,
+		autoResponse: true,
+		timeout:      DefaultTimeout,
+		responses:    make(map[string]*pendingResponse),
+	}
+}
+
+// SetAutoResponse 设置工具结果全部发回后是否自动触发 response.create，默认开启
+func (d *Dispatcher) SetAutoResponse(autoResponse bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.autoResponse = autoResponse
+}
+
+// SetTimeout 设置未通过 Registry.SetTimeout 单独指定超时的工具的默认超时时间，小于等于 0 表示不限制
+func (d *Dispatcher) SetTimeout(timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.timeout = timeout
+}
+
+// Observe 处理一个服务端事件，工具在后台调用，不会阻塞事件回调。event 为 nil 表示连接已断开
+func (d *Dispatcher) Observe(event *events.Event) {
+	if event == nil {
+		d.cancelAll(false)
+		d.endSession("")
+		return
+	}
+	switch event.Type {
+	case events.RealtimeServerEventSessionCreated:
+		if event.Session != nil {
+			d.endSession(event.Session.ID)
+		}
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDone:
+		if !d.registry.Has(event.Name) {
+			return
+		}
+		call := &Call{
+			ResponseID:  event.ResponseID,
+			ItemID:      event.ItemID,
+			CallID:      event.CallID,
+			Name:        event.Name,
+			Arguments:   event.Arguments,
+			OutputIndex: event.OutputIndex,
+		}
+		d.mu.Lock()
+		p := d.responseLocked(event.ResponseID)
+		pc := &pendingCall{call: call}
+		p.calls = append(p.calls, pc)
+		timeout := d.timeout
+		if t := d.registry.Timeout(call.Name); t > 0 {
+			timeout = t
+		}
+		ctx := p.ctx
+		sessionID := d.sessionID
+		d.mu.Unlock()
+		go d.invoke(ctx, sessionID, pc, timeout)
+	case events.RealtimeServerEventInputAudioBufferSpeechStarted:
+		// 用户打断，取消进行中的调用，由新一轮对话决定是否重新调用
+		d.cancelAll(true)
+	case events.RealtimeServerEventResponseDone:
+		responseID := event.ResponseID
+		if event.Response != nil && event.Response.ID != "" {
+			responseID = event.Response.ID
+		}
+		d.mu.Lock()
+		p, ok := d.responses[responseID]
+		if ok {
+			p.done = true
+			if event.Response != nil && event.Response.Status == events.ResponseStatusCancelled && !p.cancelled {
+				p.cancelled = true
+				p.cancel()
+			}
+		}
+		d.mu.Unlock()
+		if ok {
+			d.maybeFlush(responseID)
+		}
+	}
+}
+
+// Cancel 取消所有进行中的调用，已取消的调用会以错误结果发回模型，但不会触发 response.create。
+// 客户端发送 response.cancel 时会调用该方法
+func (d *Dispatcher) Cancel() {
+	d.cancelAll(true)
+}
+
+func (d *Dispatcher) cancelAll(flush bool) {
+	d.mu.Lock()
+	ids := make([]string, 0, len(d.responses))
+	for id, p := range d.responses {
+		if !p.cancelled {
+			p.cancelled = true
+			p.cancel()
+		}
+		// 被取消的响应不会再有 response.done 之外的调用，直接视为结束
+		p.done = true
+		if !flush {
+			delete(d.responses, id)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	d.mu.Unlock()
+	for _, id := range ids {
+		d.maybeFlush(id)
+	}
+}
+
+// endSession 切换到新的会话，旧会话结束时通知注册表，next 为空表示连接已断开
+func (d *Dispatcher) endSession(next string) {
+	d.mu.Lock()
+	prev := d.sessionID
+	d.sessionID = next
+	d.mu.Unlock()
+	if prev != "" && prev != next {
+		d.registry.EndSession(prev)
+	}
+}
+
+func (d *Dispatcher) invoke(ctx context.Context, sessionID string, pc *pendingCall, timeout time.Duration) {
+	call := pc.call
+	var callCtx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		callCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		output, err := d.registry.InvokeCall(callCtx, &Invocation{
+			SessionID:  sessionID,
+			ResponseID: call.ResponseID,
+			ItemID:     call.ItemID,
+			CallID:     call.CallID,
+			Name:       call.Name,
+			Arguments:  call.Arguments,
+		})
+		results <- result{output, err}
+	}()
+
+	var output string
+	var err error
+	select {
+	case res := <-results:
+		output, err = res.output, res.err
+	case <-callCtx.Done():
+		// 处理函数可能没有响应 ctx，不再等待其返回
+	}
+	if callCtx.Err() != nil && (err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		if ctx.Err() != nil {
+			err = ErrCancelled
+		} else {
+			err = fmt.Errorf("tool %s timed out after %v", call.Name, timeout)
+		}
+	}
+	if err != nil {
+		log.Printf("[ToolDispatcher] Tool %s failed, call_id: %s, err: %v\n", call.Name, call.CallID, err)
+		output = ErrorOutput(err)
+	}
+
+	d.mu.Lock()
+	pc.output, pc.finished = output, true
+	d.mu.Unlock()
+	d.maybeFlush(call.ResponseID)
+}
+
+// maybeFlush 在响应结束且所有调用都完成后，按顺序发回结果并触发一次 response.create
+func (d *Dispatcher) maybeFlush(responseID string) {
+	d.mu.Lock()
+	p, ok := d.responses[responseID]
+	if !ok || !p.done {
+		d.mu.Unlock()
+		return
+	}
+	for _, pc := range p.calls {
+		if !pc.finished {
+			d.mu.Unlock()
+			return
+		}
+	}
+	delete(d.responses, responseID)
+	p.cancel()
+	calls := append([]*pendingCall(nil), p.calls...)
+	autoResponse := d.autoResponse && !p.cancelled
+	d.mu.Unlock()
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].call.OutputIndex < calls[j].call.OutputIndex
+	})
+	for _, pc := range calls {
+		if err := d.sender.Send(OutputEvent(pc.call.CallID, pc.output)); err != nil {
+			log.Printf("[ToolDispatcher] Send function_call_output failed, call_id: %s, err: %v\n", pc.call.CallID, err)
+			return
+		}
+	}
+	if !autoResponse || len(calls) == 0 {
+		return
+	}
+	if err := d.sender.Send(&events.Event{Type: events.RealtimeClientEventResponseCreate}); err != nil {
+		log.Printf("[ToolDispatcher] Send response.create failed, err: %v\n", err)
+	}
+}
+
+func (d *Dispatcher) responseLocked(responseID string) *pendingResponse {
+	p, ok := d.responses[responseID]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		p = &pendingResponse{ctx: ctx, cancel: cancel}
+		d.responses[responseID] = p
+	}
+	return p
+}
+
+// OutputEvent 构造一个 function_call_output 条目的 conversation.item.create 事件
+func OutputEvent(callID, output string) *events.Event {
+	return &events.Event{
+		Type: events.RealtimeClientEventConversationItemCreate,
+		Item: &events.Item{
+			Object: events.ItemObjectRealTimeItem,
+			Type:   events.ItemTypeFunctionCallOutput,
+			Status: events.ItemStatusCompleted,
+			CallId: callID,
+			Output: &output,
+		},
+	}
+}
@@ -0,0 +1,156 @@
+package toolcall
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []*events.Event
+}
+
+func (s *recordingSender) Send(event *events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+func (s *recordingSender) wait(t *testing.T, n int) []*events.Event {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		if len(s.sent) >= n {
+			sent := append([]*events.Event(nil), s.sent...)
+			s.mu.Unlock()
+			return sent
+		}
+		s.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d events", n)
+	return nil
+}
+
+type weatherArgs struct {
+	Location string `json:"location"`
+}
+
+func TestDispatcherInvokesRegisteredTool(t *testing.T) {
+	registry := NewRegistry()
+	err := RegisterFunc(registry, "SearchWeather", "查询指定城市的天气", events.ToolParameters{}, func(ctx context.Context, args weatherArgs) (map[string]string, error) {
+		if args.Location == "" {
+			return nil, fmt.Errorf("location is required")
+		}
+		return map[string]string{"location": args.Location, "weather": "晴"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tools := registry.MergeTools([]events.Tool{{Name: "SearchWeather"}, {Name: "Other"}}); len(tools) != 2 || tools[0].Name != "Other" || tools[1].Parameters.Type != "object" {
+		t.Fatalf("unexpected merged tools: %+v", tools)
+	}
+
+	sender := &recordingSender{}
+	dispatcher := NewDispatcher(registry, sender)
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ResponseID: "resp_1", CallID: "call_1", Name: "SearchWeather", Arguments: `{"location":"北京"}`})
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ResponseID: "resp_1", CallID: "call_2", Name: "Unknown", Arguments: `{}`})
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_1"}})
+
+	sent := sender.wait(t, 2)
+	output, create := sent[0], sent[1]
+	if output.Item == nil || output.Item.CallId != "call_1" || *output.Item.Output != `{"location":"北京","weather":"晴"}` {
+		t.Fatalf("unexpected function_call_output: %s", output.ToJson())
+	}
+	if create.Type != events.RealtimeClientEventResponseCreate {
+		t.Fatalf("expected response.create, got %s", create.Type)
+	}
+
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ResponseID: "resp_2", CallID: "call_3", Name: "SearchWeather", Arguments: `{}`})
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_2"}})
+	if sent = sender.wait(t, 4); *sent[2].Item.Output != `{"error":"location is required"}` {
+		t.Fatalf("unexpected error output: %s", sent[2].ToJson())
+	}
+}
+
+func TestDispatcherParallelOrderAndTimeout(t *testing.T) {
+	registry := NewRegistry()
+	release := make(chan struct{})
+	_ = registry.Register(events.Tool{Name: "Slow"}, func(ctx context.Context, arguments string) (string, error) {
+		select {
+		case <-release:
+			return "slow", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	_ = registry.Register(events.Tool{Name: "Fast"}, func(ctx context.Context, arguments string) (string, error) {
+		return "fast", nil
+	})
+	_ = registry.Register(events.Tool{Name: "Stuck"}, func(ctx context.Context, arguments string) (string, error) {
+		// 不响应 ctx 的处理函数也会按超时返回
+		<-release
+		return "stuck", nil
+	})
+	_ = registry.SetTimeout("Stuck", 50*time.Millisecond)
+
+	sender := &recordingSender{}
+	dispatcher := NewDispatcher(registry, sender)
+	// 默认超时为 0 时不限制，只有 Stuck 单独设置的超时生效
+	dispatcher.SetTimeout(0)
+	for i, name := range []string{"Slow", "Fast", "Stuck"} {
+		dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ResponseID: "resp_1", CallID: name, Name: name, OutputIndex: i})
+	}
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_1"}})
+
+	time.Sleep(100 * time.Millisecond)
+	sender.mu.Lock()
+	if len(sender.sent) != 0 {
+		t.Fatalf("outputs must wait for all calls, sent %d", len(sender.sent))
+	}
+	sender.mu.Unlock()
+	close(release)
+
+	sent := sender.wait(t, 4)
+	for i, want := range []string{"Slow", "Fast", "Stuck"} {
+		if sent[i].Item == nil || sent[i].Item.CallId != want {
+			t.Fatalf("output %d should belong to %s, got %s", i, want, sent[i].ToJson())
+		}
+	}
+	if *sent[0].Item.Output != "slow" || *sent[1].Item.Output != "fast" || *sent[2].Item.Output != `{"error":"tool Stuck timed out after 50ms"}` {
+		t.Fatalf("unexpected outputs: %s, %s, %s", *sent[0].Item.Output, *sent[1].Item.Output, *sent[2].Item.Output)
+	}
+	if sent[3].Type != events.RealtimeClientEventResponseCreate {
+		t.Fatalf("expected a single response.create, got %s", sent[3].Type)
+	}
+}
+
+func TestDispatcherCancelOnBargeIn(t *testing.T) {
+	registry := NewRegistry()
+	_ = registry.Register(events.Tool{Name: "Slow"}, func(ctx context.Context, arguments string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	sender := &recordingSender{}
+	dispatcher := NewDispatcher(registry, sender)
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ResponseID: "resp_1", CallID: "call_1", Name: "Slow"})
+	dispatcher.Observe(&events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted})
+
+	sent := sender.wait(t, 1)
+	if *sent[0].Item.Output != `{"error":"tool call cancelled"}` {
+		t.Fatalf("unexpected output: %s", *sent[0].Item.Output)
+	}
+	time.Sleep(50 * time.Millisecond)
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	if len(sender.sent) != 1 {
+		t.Fatalf("cancelled responses should not trigger response.create")
+	}
+}
@@ -0,0 +1,234 @@
+package toolcall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Invocation 一次工具调用，中间件可以修改 Name 以外的字段，例如改写 Arguments
+type Invocation struct {
+	SessionID  string
+	ResponseID string
+	ItemID     string
+	CallID     string
+	Name       string
+	Arguments  string
+}
+
+// Next 调用链中的下一环
+type Next func(ctx context.Context, inv *Invocation) (string, error)
+
+// Middleware 工具调用中间件，调用 next 继续执行，直接返回则跳过后续中间件和处理函数
+type Middleware func(ctx context.Context, inv *Invocation, next Next) (string, error)
+
+// Use 添加中间件，先添加的中间件位于调用链外层
+func (r *Registry) Use(middlewares ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	chain := make([]Middleware, 0, len(r.chain)+len(middlewares))
+	r.chain = append(append(chain, r.chain...), middlewares...)
+}
+
+// DeniedError 调用被中间件拒绝，Reason 会作为 function_call_output 发回模型
+type DeniedError struct {
+	Tool   string
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("tool %s call denied: %s", e.Tool, e.Reason)
+}
+
+// Deny 构造一个拒绝调用的错误
+func Deny(inv *Invocation, reason string) error {
+	return &DeniedError{Tool: inv.Name, Reason: reason}
+}
+
+// Approver 人工审批函数，返回是否批准以及拒绝原因。ctx 在审批超时后取消
+type Approver func(ctx context.Context, inv *Invocation) (approved bool, reason string, err error)
+
+// Approval 调用前需人工审批，超时未审批视为拒绝。tools 为空时对所有工具生效。
+// 审批等待时间计入工具调用超时，需要较长审批时间时应同时调大工具超时
+func Approval(approver Approver, timeout time.Duration, tools ...string) Middleware {
+	match := toolSet(tools)
+	return func(ctx context.Context, inv *Invocation, next Next) (string, error) {
+		if !match(inv.Name) {
+			return next(ctx, inv)
+		}
+		approveCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		type result struct {
+			approved bool
+			reason   string
+			err      error
+		}
+		results := make(chan result, 1)
+		go func() {
+			approved, reason, err := approver(approveCtx, inv)
+			results <- result{approved, reason, err}
+		}()
+		select {
+		case res := <-results:
+			if res.err != nil {
+				return "", res.err
+			}
+			if !res.approved {
+				if res.reason == "" {
+					res.reason = "rejected by user"
+				}
+				return "", Deny(inv, res.reason)
+			}
+		case <-approveCtx.Done():
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", Deny(inv, fmt.Sprintf("approval timed out after %v", timeout))
+		}
+		return next(ctx, inv)
+	}
+}
+
+// AuditRecord 一次工具调用的审计记录
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	SessionID  string    `json:"session_id,omitempty"`
+	ResponseID string    `json:"response_id,omitempty"`
+	CallID     string    `json:"call_id,omitempty"`
+	Name       string    `json:"name"`
+	Arguments  string    `json:"arguments"`
+	// Original 参数被中间件改写时的原始参数
+	Original string        `json:"original_arguments,omitempty"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Denied   bool          `json:"denied,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Audit 每次调用结束后将审计记录交给 sink，包括被拒绝和失败的调用。
+// 放在调用链最外层时可以记录其他中间件的拒绝和改写
+func Audit(sink func(record AuditRecord)) Middleware {
+	return func(ctx context.Context, inv *Invocation, next Next) (string, error) {
+		start := time.Now()
+		original := inv.Arguments
+		output, err := next(ctx, inv)
+		record := AuditRecord{
+			Time:       start,
+			SessionID:  inv.SessionID,
+			ResponseID: inv.ResponseID,
+			CallID:     inv.CallID,
+			Name:       inv.Name,
+			Arguments:  inv.Arguments,
+			Output:     output,
+			Duration:   time.Since(start),
+		}
+		if inv.Arguments != original {
+			record.Original = original
+		}
+		if err != nil {
+			var denied *DeniedError
+			record.Denied = errors.As(err, &denied)
+			record.Error = err.Error()
+		}
+		sink(record)
+		return output, err
+	}
+}
+
+// CallLimiter 按会话限制各工具的调用次数，会话结束后调用 Reset 清除该会话的计数
+type CallLimiter struct {
+	limits map[string]int
+
+	mu     sync.Mutex
+	counts map[string]map[string]int
+}
+
+// NewCallLimiter limits 的 key 为工具名，会话以 Invocation.SessionID 区分。
+// 可通过 registry.OnSessionEnd(limiter.Reset) 在会话结束时自动清除计数
+func NewCallLimiter(limits map[string]int) *CallLimiter {
+	return &CallLimiter{limits: limits, counts: make(map[string]map[string]int)}
+}
+
+// Middleware 返回限制调用次数的中间件，超出后拒绝调用
+func (l *CallLimiter) Middleware() Middleware {
+	return func(ctx context.Context, inv *Invocation, next Next) (string, error) {
+		limit, ok := l.limits[inv.Name]
+		if !ok {
+			return next(ctx, inv)
+		}
+		if !l.acquire(inv.SessionID, inv.Name, limit) {
+			return "", Deny(inv, fmt.Sprintf("call limit of %d per session reached", limit))
+		}
+		return next(ctx, inv)
+	}
+}
+
+// Reset 清除指定会话的计数
+func (l *CallLimiter) Reset(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.counts, sessionID)
+}
+
+func (l *CallLimiter) acquire(sessionID, name string, limit int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	session, ok := l.counts[sessionID]
+	if !ok {
+		session = make(map[string]int)
+		l.counts[sessionID] = session
+	}
+	if session[name] >= limit {
+		return false
+	}
+	session[name]++
+	return true
+}
+
+// CallLimit 限制每个会话中各工具的调用次数，limits 的 key 为工具名，超出后拒绝调用。
+// 会话以 Invocation.SessionID 区分，出现新的会话时丢弃其他会话的计数，适用于只服务一个连接的注册表；
+// 多个连接共用注册表时使用 NewCallLimiter
+func CallLimit(limits map[string]int) Middleware {
+	l := NewCallLimiter(limits)
+	next := l.Middleware()
+	return func(ctx context.Context, inv *Invocation, n Next) (string, error) {
+		l.mu.Lock()
+		if _, ok := l.counts[inv.SessionID]; !ok {
+			clear(l.counts)
+		}
+		l.mu.Unlock()
+		return next(ctx, inv, n)
+	}
+}
+
+// Rewrite 调用前改写参数，rewrite 返回错误时拒绝调用，错误信息作为拒绝原因
+func Rewrite(rewrite func(ctx context.Context, inv *Invocation) error, tools ...string) Middleware {
+	match := toolSet(tools)
+	return func(ctx context.Context, inv *Invocation, next Next) (string, error) {
+		if !match(inv.Name) {
+			return next(ctx, inv)
+		}
+		if err := rewrite(ctx, inv); err != nil {
+			var denied *DeniedError
+			if errors.As(err, &denied) {
+				return "", err
+			}
+			return "", Deny(inv, err.Error())
+		}
+		return next(ctx, inv)
+	}
+}
+
+func toolSet(tools []string) func(name string) bool {
+	if len(tools) == 0 {
+		return func(string) bool { return true }
+	}
+	set := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		set[t] = true
+	}
+	return func(name string) bool { return set[name] }
+}
@@ -0,0 +1,192 @@
+package toolcall
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+type orderArgs struct {
+	SKU   string `json:"sku"`
+	Count int    `json:"count"`
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	registry := NewRegistry()
+	if err := RegisterTyped(registry, "PlaceOrder", "下单", func(ctx context.Context, args orderArgs) (string, error) {
+		return "ordered " + args.SKU, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var records []AuditRecord
+	registry.Use(
+		Audit(func(record AuditRecord) {
+			mu.Lock()
+			defer mu.Unlock()
+			records = append(records, record)
+		}),
+		CallLimit(map[string]int{"PlaceOrder": 10}),
+		Rewrite(func(ctx context.Context, inv *Invocation) error {
+			if strings.Contains(inv.Arguments, `"count":0`) {
+				inv.Arguments = strings.Replace(inv.Arguments, `"count":0`, `"count":1`, 1)
+			}
+			if strings.Contains(inv.Arguments, "FORBIDDEN") {
+				return Deny(inv, "sku is not for sale")
+			}
+			return nil
+		}, "PlaceOrder"),
+		Approval(func(ctx context.Context, inv *Invocation) (bool, string, error) {
+			if strings.Contains(inv.Arguments, "SLOW") {
+				<-ctx.Done()
+				return false, "", nil
+			}
+			return !strings.Contains(inv.Arguments, "REJECT"), "too expensive", nil
+		}, 50*time.Millisecond, "PlaceOrder"),
+	)
+
+	sender := &recordingSender{}
+	d := NewDispatcher(registry, sender)
+	d.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_1"}})
+	for i, args := range []string{
+		`{"sku":"A1","count":0}`,
+		`{"sku":"REJECT","count":1}`,
+		`{"sku":"FORBIDDEN","count":1}`,
+		`{"sku":"SLOW","count":1}`,
+	} {
+		d.Observe(&events.Event{
+			Type:        events.RealtimeServerEventResponseFunctionCallArgumentsDone,
+			ResponseID:  "resp_1",
+			CallID:      "call_" + string(rune('1'+i)),
+			Name:        "PlaceOrder",
+			Arguments:   args,
+			OutputIndex: i,
+		})
+	}
+	d.Observe(&events.Event{Type: events.RealtimeServerEventResponseDone, Response: &events.Response{ID: "resp_1"}})
+
+	sent := sender.wait(t, 5)
+	outputs := make([]string, 0, 4)
+	for _, e := range sent[:4] {
+		outputs = append(outputs, *e.Item.Output)
+	}
+	if outputs[0] != "ordered A1" {
+		t.Errorf("unexpected output: %s", outputs[0])
+	}
+	if outputs[1] != `{"error":"denied","message":"too expensive"}` {
+		t.Errorf("unexpected output: %s", outputs[1])
+	}
+	if outputs[2] != `{"error":"denied","message":"sku is not for sale"}` {
+		t.Errorf("unexpected output: %s", outputs[2])
+	}
+	if outputs[3] != `{"error":"denied","message":"approval timed out after 50ms"}` {
+		t.Errorf("unexpected output: %s", outputs[3])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 4 {
+		t.Fatalf("expected 4 audit records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.SessionID != "sess_1" || record.Name != "PlaceOrder" {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		if record.CallID == "call_1" && (record.Original != `{"sku":"A1","count":0}` || record.Arguments != `{"sku":"A1","count":1}` || record.Denied) {
+			t.Errorf("rewrite not audited: %+v", record)
+		}
+	}
+}
+
+func TestCallLimitPerSession(t *testing.T) {
+	registry := NewRegistry()
+	if err := RegisterTyped(registry, "PlaceOrder", "下单", func(ctx context.Context, args orderArgs) (string, error) {
+		return "ordered", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Use(CallLimit(map[string]int{"PlaceOrder": 2}))
+
+	invoke := func(sessionID string) error {
+		_, err := registry.InvokeCall(context.Background(), &Invocation{SessionID: sessionID, Name: "PlaceOrder", Arguments: `{"sku":"A1","count":1}`})
+		return err
+	}
+	for i := 0; i < 2; i++ {
+		if err := invoke("sess_1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := invoke("sess_1"); ErrorOutput(err) != `{"error":"denied","message":"call limit of 2 per session reached"}` {
+		t.Errorf("expected call limit, got %v", err)
+	}
+	if err := invoke("sess_2"); err != nil {
+		t.Errorf("limit should be per session, got %v", err)
+	}
+	// 出现新的会话后旧会话的计数被丢弃
+	if err := invoke("sess_1"); err != nil {
+		t.Errorf("counts of old sessions should be dropped, got %v", err)
+	}
+}
+
+func TestCallLimiterResetOnSessionEnd(t *testing.T) {
+	registry := NewRegistry()
+	if err := RegisterTyped(registry, "PlaceOrder", "下单", func(ctx context.Context, args orderArgs) (string, error) {
+		return "ordered", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	limiter := NewCallLimiter(map[string]int{"PlaceOrder": 1})
+	registry.Use(limiter.Middleware())
+	registry.OnSessionEnd(limiter.Reset)
+
+	invoke := func(sessionID string) error {
+		_, err := registry.InvokeCall(context.Background(), &Invocation{SessionID: sessionID, Name: "PlaceOrder", Arguments: `{"sku":"A1","count":1}`})
+		return err
+	}
+	for _, id := range []string{"sess_1", "sess_2"} {
+		if err := invoke(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := invoke("sess_1"); err == nil {
+		t.Fatal("expected call limit")
+	}
+
+	d := NewDispatcher(registry, &recordingSender{})
+	d.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_1"}})
+	d.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_3"}})
+	if len(limiter.counts) != 1 {
+		t.Errorf("ended session should be reset, counts: %v", limiter.counts)
+	}
+	if err := invoke("sess_1"); err != nil {
+		t.Errorf("limit should be reset after session end, got %v", err)
+	}
+	limiter.Reset("sess_1")
+	limiter.Reset("sess_2")
+	if len(limiter.counts) != 0 {
+		t.Errorf("unexpected counts after reset: %v", limiter.counts)
+	}
+}
+
+func TestApprovalTimeout(t *testing.T) {
+	registry := NewRegistry()
+	if err := RegisterTyped(registry, "PlaceOrder", "下单", func(ctx context.Context, args orderArgs) (string, error) {
+		return "ordered", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Use(Approval(func(ctx context.Context, inv *Invocation) (bool, string, error) {
+		<-ctx.Done()
+		return true, "", nil
+	}, 20*time.Millisecond))
+
+	_, err := registry.Invoke(context.Background(), "PlaceOrder", `{"sku":"A1","count":1}`)
+	if err == nil || ErrorOutput(err) != `{"error":"denied","message":"approval timed out after 20ms"}` {
+		t.Fatalf("expected approval timeout, got %v", err)
+	}
+}
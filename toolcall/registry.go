@@ -0,0 +1,236 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Handler 工具的处理函数，arguments 为模型生成的 JSON 参数，返回值作为 function_call_output 发回模型
+type Handler func(ctx context.Context, arguments string) (string, error)
+
+type registeredTool struct {
+	tool    events.Tool
+	handler Handler
+	timeout time.Duration
+}
+
+// Registry 工具注册表，保存工具定义和对应的 Go 处理函数
+type Registry struct {
+	mu       sync.RWMutex
+	tools    map[string]*registeredTool
+	order    []string
+	validate bool
+	chain    []Middleware
+	// sessionEnd 会话结束时的回调
+	sessionEnd []func(sessionID string)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]*registeredTool), validate: true}
+}
+
+// OnSessionEnd 注册会话结束时的回调，Dispatcher 在连接断开或收到新的 session.created 时触发
+func (r *Registry) OnSessionEnd(fn func(sessionID string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessionEnd = append(r.sessionEnd, fn)
+}
+
+// EndSession 通知注册表会话已结束，依次调用 OnSessionEnd 注册的回调
+func (r *Registry) EndSession(sessionID string) {
+	r.mu.RLock()
+	hooks := slices.Clone(r.sessionEnd)
+	r.mu.RUnlock()
+	for _, fn := range hooks {
+		fn(sessionID)
+	}
+}
+
+// SetValidateArguments 设置调用工具前是否按参数定义校验参数，默认开启
+func (r *Registry) SetValidateArguments(validate bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validate = validate
+}
+
+// Register 注册一个工具，同名工具会被覆盖
+func (r *Registry) Register(tool events.Tool, handler Handler) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %s handler is nil", tool.Name)
+	}
+	if tool.Type == "" {
+		tool.Type = "function"
+	}
+	if tool.Parameters.Type == "" {
+		tool.Parameters.Type = "object"
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[tool.Name]; !ok {
+		r.order = append(r.order, tool.Name)
+	}
+	r.tools[tool.Name] = &registeredTool{tool: tool, handler: handler}
+	return nil
+}
+
+// RegisterFunc 注册一个类型安全的工具，参数 JSON 会被解码为 T，返回值 R 为字符串时原样返回，否则编码为 JSON
+func RegisterFunc[T any, R any](r *Registry, name, description string, parameters events.ToolParameters, fn func(ctx context.Context, args T) (R, error)) error {
+	return r.Register(events.Tool{
+		Type:        "function",
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+	}, func(ctx context.Context, arguments string) (string, error) {
+		args, err := DecodeArguments[T](arguments)
+		if err != nil {
+			return "", err
+		}
+		result, err := fn(ctx, args)
+		if err != nil {
+			return "", err
+		}
+		return encodeResult(result)
+	})
+}
+
+// Unregister 移除一个工具
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tools[name]; !ok {
+		return
+	}
+	delete(r.tools, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// SetTimeout 为指定工具单独设置调用超时时间，需在注册之后调用
+func (r *Registry) SetTimeout(name string, timeout time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tools[name]
+	if !ok {
+		return fmt.Errorf("tool %s not found", name)
+	}
+	t.timeout = timeout
+	return nil
+}
+
+// Timeout 返回工具单独设置的超时时间，未设置时返回 0
+func (r *Registry) Timeout(name string) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, ok := r.tools[name]; ok {
+		return t.timeout
+	}
+	return 0
+}
+
+// Has 判断工具是否已注册
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.tools[name]
+	return ok
+}
+
+// Tool 返回已注册工具的定义
+func (r *Registry) Tool(name string) (events.Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tools[name]
+	if !ok {
+		return events.Tool{}, false
+	}
+	return t.tool, true
+}
+
+// Tools 按注册顺序返回所有工具定义，用于 session.update
+func (r *Registry) Tools() []events.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := make([]events.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// MergeTools 将注册表中的工具合并到 tools 中，同名工具以注册表为准
+func (r *Registry) MergeTools(tools []events.Tool) []events.Tool {
+	registered := r.Tools()
+	merged := make([]events.Tool, 0, len(tools)+len(registered))
+	for _, t := range tools {
+		if !r.Has(t.Name) {
+			merged = append(merged, t)
+		}
+	}
+	return append(merged, registered...)
+}
+
+// Invoke 调用指定工具，参数校验失败时返回 *ValidationError 且不会调用处理函数
+func (r *Registry) Invoke(ctx context.Context, name, arguments string) (string, error) {
+	return r.InvokeCall(ctx, &Invocation{Name: name, Arguments: arguments})
+}
+
+// InvokeCall 依次经过中间件后调用工具，参数校验在中间件之后进行，以便中间件改写参数
+func (r *Registry) InvokeCall(ctx context.Context, inv *Invocation) (string, error) {
+	r.mu.RLock()
+	chain := r.chain
+	r.mu.RUnlock()
+	next := r.invoke
+	for i := len(chain) - 1; i >= 0; i-- {
+		mw, inner := chain[i], next
+		next = func(ctx context.Context, inv *Invocation) (string, error) {
+			return mw(ctx, inv, inner)
+		}
+	}
+	return next(ctx, inv)
+}
+
+func (r *Registry) invoke(ctx context.Context, inv *Invocation) (string, error) {
+	r.mu.RLock()
+	t, ok := r.tools[inv.Name]
+	validate := r.validate
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("tool %s not found", inv.Name)
+	}
+	if validate {
+		if err := Validate(t.tool.Parameters, inv.Arguments); err != nil {
+			err.(*ValidationError).Tool = inv.Name
+			return "", err
+		}
+	}
+	return t.handler(ctx, inv.Arguments)
+}
+
+func encodeResult(result any) (string, error) {
+	switch v := result.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case json.RawMessage:
+		return string(v), nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("encode result failed: %w", err)
+	}
+	return string(data), nil
+}
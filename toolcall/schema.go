@@ -0,0 +1,256 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// SchemaFor 根据参数结构体 T 生成工具的参数定义。
+//
+// 字段名取自 json 标签，json:"-" 的字段会被忽略，匿名嵌入的结构体字段会被展开；
+// description 标签为字段描述；jsonschema 标签以逗号分隔，支持：
+//
+//	required / optional   显式指定是否必填
+//	enum=a|b|c            可选值
+//	default=x             默认值
+//	format=email          字符串格式
+//	pattern=^[a-z]+$      字符串正则
+//	minimum=1,maximum=10  数值范围
+//	minLength=1,maxLength=64,minItems=1,maxItems=10
+//
+// 未显式指定时，非指针且 json 标签不含 omitempty 的字段视为必填。
+func SchemaFor[T any]() (events.ToolParameters, error) {
+	return Schema(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Schema 根据结构体类型生成工具的参数定义
+func Schema(t reflect.Type) (events.ToolParameters, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return events.ToolParameters{}, fmt.Errorf("tool arguments must be a struct, got %s", t)
+	}
+	property, err := schemaOf(t, map[reflect.Type]bool{})
+	if err != nil {
+		return events.ToolParameters{}, err
+	}
+	required := property.Required
+	if required == nil {
+		required = []string{}
+	}
+	return events.ToolParameters{Type: "object", Properties: property.Properties, Required: required}, nil
+}
+
+// DecodeArguments 将函数调用的参数 JSON 解码为 T
+func DecodeArguments[T any](arguments string) (T, error) {
+	var args T
+	if strings.TrimSpace(arguments) == "" {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return args, fmt.Errorf("decode arguments failed: %w", err)
+	}
+	return args, nil
+}
+
+// RegisterTyped 注册一个工具，参数定义由 SchemaFor[T] 自动生成
+func RegisterTyped[T any, R any](r *Registry, name, description string, fn func(ctx context.Context, args T) (R, error)) error {
+	parameters, err := SchemaFor[T]()
+	if err != nil {
+		return err
+	}
+	return RegisterFunc(r, name, description, parameters, fn)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) (events.ToolProperty, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return events.ToolProperty{Type: "string", Format: "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return events.ToolProperty{Type: "string"}, nil
+	case reflect.Bool:
+		return events.ToolProperty{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return events.ToolProperty{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return events.ToolProperty{Type: "number"}, nil
+	case reflect.Slice, reflect.Array:
+		// encoding/json 将 []byte 编码为 base64 字符串
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return events.ToolProperty{Type: "string", ContentEncoding: "base64"}, nil
+		}
+		items, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return events.ToolProperty{}, err
+		}
+		return events.ToolProperty{Type: "array", Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return events.ToolProperty{}, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return events.ToolProperty{}, err
+		}
+		return events.ToolProperty{Type: "object", AdditionalProperties: &events.AdditionalProperties{Allowed: true, Schema: &values}}, nil
+	case reflect.Interface:
+		return events.ToolProperty{}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return events.ToolProperty{}, fmt.Errorf("recursive type %s is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		property := events.ToolProperty{Type: "object", Properties: map[string]events.ToolProperty{}}
+		if err := addFields(&property, t, visiting); err != nil {
+			return events.ToolProperty{}, err
+		}
+		return property, nil
+	}
+	return events.ToolProperty{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func addFields(object *events.ToolProperty, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addFields(object, embedded, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property, err := schemaOf(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		property.Description = field.Tag.Get("description")
+		required := !omitempty && field.Type.Kind() != reflect.Pointer
+		for _, option := range splitTag(field.Tag.Get("jsonschema")) {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "required":
+				required = true
+			case "optional":
+				required = false
+			case "enum":
+				for _, v := range strings.Split(value, "|") {
+					parsed, err := parseValue(property.Type, v)
+					if err != nil {
+						return fmt.Errorf("field %s enum: %w", field.Name, err)
+					}
+					property.Enum = append(property.Enum, parsed)
+				}
+			case "default":
+				parsed, err := parseValue(property.Type, value)
+				if err != nil {
+					return fmt.Errorf("field %s default: %w", field.Name, err)
+				}
+				property.Default = parsed
+			case "format":
+				property.Format = value
+			case "pattern":
+				property.Pattern = value
+			case "minimum", "maximum":
+				number, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return fmt.Errorf("field %s %s: %w", field.Name, key, err)
+				}
+				if key == "minimum" {
+					property.Minimum = &number
+				} else {
+					property.Maximum = &number
+				}
+			case "minLength", "maxLength", "minItems", "maxItems":
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("field %s %s: %w", field.Name, key, err)
+				}
+				switch key {
+				case "minLength":
+					property.MinLength = &n
+				case "maxLength":
+					property.MaxLength = &n
+				case "minItems":
+					property.MinItems = &n
+				case "maxItems":
+					property.MaxItems = &n
+				}
+			}
+		}
+		object.Properties[name] = property
+		if required {
+			object.Required = append(object.Required, name)
+		}
+	}
+	return nil
+}
+
+func jsonName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+func splitTag(tag string) []string {
+	var options []string
+	for _, option := range strings.Split(tag, ",") {
+		if option = strings.TrimSpace(option); option != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+func parseValue(typ, value string) (any, error) {
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
@@ -0,0 +1,87 @@
+package toolcall
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type Paging struct {
+	Page int `json:"page" jsonschema:"default=1"`
+}
+
+type searchArgs struct {
+	Paging
+	Query   string   `json:"query" description:"搜索关键词"`
+	Unit    string   `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit,default=celsius"`
+	Days    *int     `json:"days" jsonschema:"enum=1|3|7"`
+	Tags    []string `json:"tags,omitempty" jsonschema:"required"`
+	Filters []struct {
+		Field string `json:"field"`
+		Exact bool   `json:"exact,omitempty"`
+	} `json:"filters,omitempty"`
+	Internal string `json:"-"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	parameters, err := SchemaFor[searchArgs]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{` +
+		`"days":{"type":"integer","enum":[1,3,7]},` +
+		`"filters":{"type":"array","items":{"type":"object","properties":{"exact":{"type":"boolean"},"field":{"type":"string"}},"required":["field"]}},` +
+		`"page":{"type":"integer","default":1},` +
+		`"query":{"type":"string","description":"搜索关键词"},` +
+		`"tags":{"type":"array","items":{"type":"string"}},` +
+		`"unit":{"type":"string","enum":["celsius","fahrenheit"],"default":"celsius"}},` +
+		`"required":["page","query","tags"]}`
+	if string(data) != want {
+		t.Fatalf("unexpected schema:\n%s\nwant:\n%s", data, want)
+	}
+
+	if _, err = SchemaFor[string](); err == nil {
+		t.Fatalf("expected error for non-struct arguments")
+	}
+
+	args, err := DecodeArguments[searchArgs](`{"query":"北京","days":3,"filters":[{"field":"city"}]}`)
+	if err != nil || args.Query != "北京" || *args.Days != 3 || args.Filters[0].Field != "city" {
+		t.Fatalf("unexpected arguments: %+v, %v", args, err)
+	}
+}
+
+type rangeArgs struct {
+	Limit   int               `json:"limit" jsonschema:"minimum=1,maximum=10"`
+	Email   string            `json:"email,omitempty" jsonschema:"format=email,maxLength=64"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Created time.Time         `json:"created,omitempty"`
+	Avatar  []byte            `json:"avatar,omitempty"`
+}
+
+func TestSchemaForConstraints(t *testing.T) {
+	parameters, err := SchemaFor[rangeArgs]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(parameters)
+	want := `{"type":"object","properties":{` +
+		`"avatar":{"type":"string","contentEncoding":"base64"},` +
+		`"created":{"type":"string","format":"date-time"},` +
+		`"email":{"type":"string","format":"email","maxLength":64},` +
+		`"labels":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"limit":{"type":"integer","minimum":1,"maximum":10}},` +
+		`"required":["limit"]}`
+	if string(data) != want {
+		t.Fatalf("unexpected schema:\n%s\nwant:\n%s", data, want)
+	}
+
+	args, err := DecodeArguments[rangeArgs](`{"limit":1,"avatar":"AQID"}`)
+	if err != nil || !bytes.Equal(args.Avatar, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected arguments: %+v, %v", args, err)
+	}
+}
@@ -0,0 +1,215 @@
+package toolcall
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// PartialCall 正在生成中的函数调用
+type PartialCall struct {
+	ResponseID string
+	ItemID     string
+	CallID     string
+	Name       string
+	// Arguments 目前收到的原始参数文本
+	Arguments string
+	// Parsed 对 Arguments 补全后解析出的参数，尚无法解析时为 nil
+	Parsed map[string]any
+	Done   bool
+}
+
+// ArgumentAssembler 按 call_id 拼接 response.function_call_arguments.delta，
+// 并在每次更新时尽量解析不完整的参数 JSON，便于界面在参数生成完成前展示工具调用进度
+type ArgumentAssembler struct {
+	mu       sync.Mutex
+	calls    map[string]*PartialCall
+	items    map[string]*PartialCall
+	onUpdate func(call PartialCall)
+}
+
+// NewArgumentAssembler 创建参数拼接器，onUpdate 在每次参数更新和完成时调用，可以为 nil
+func NewArgumentAssembler(onUpdate func(call PartialCall)) *ArgumentAssembler {
+	return &ArgumentAssembler{
+		calls:    make(map[string]*PartialCall),
+		items:    make(map[string]*PartialCall),
+		onUpdate: onUpdate,
+	}
+}
+
+// Observe 处理一个服务端事件
+func (a *ArgumentAssembler) Observe(event *events.Event) {
+	if event == nil {
+		return
+	}
+	var updated *PartialCall
+	a.mu.Lock()
+	switch event.Type {
+	case events.RealtimeServerEventResponseOutputItemAdded:
+		if event.Item != nil && event.Item.Type == events.ItemTypeFunctionCall {
+			call := a.callLocked(event.Item.CallId, event.Item.ID)
+			call.ResponseID, call.Name = event.ResponseID, event.Item.Name
+		}
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDelta:
+		call := a.callLocked(event.CallID, event.ItemID)
+		if event.ResponseID != "" {
+			call.ResponseID = event.ResponseID
+		}
+		if event.Name != "" {
+			call.Name = event.Name
+		}
+		call.Arguments += event.Delta
+		if parsed, ok := ParsePartialJSON(call.Arguments).(map[string]any); ok {
+			call.Parsed = parsed
+		}
+		updated = call
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDone:
+		call := a.callLocked(event.CallID, event.ItemID)
+		if event.Name != "" {
+			call.Name = event.Name
+		}
+		call.Arguments, call.Done = event.Arguments, true
+		if parsed, ok := ParsePartialJSON(call.Arguments).(map[string]any); ok {
+			call.Parsed = parsed
+		}
+		updated = call
+	case events.RealtimeServerEventResponseDone:
+		// 响应结束后清理已完成的调用
+		for id, call := range a.calls {
+			if call.Done {
+				delete(a.calls, id)
+				delete(a.items, call.ItemID)
+			}
+		}
+	}
+	var snapshot PartialCall
+	if updated != nil {
+		snapshot = *updated
+	}
+	a.mu.Unlock()
+	if updated != nil && a.onUpdate != nil {
+		a.onUpdate(snapshot)
+	}
+}
+
+// Call 返回指定 call_id 的当前状态
+func (a *ArgumentAssembler) Call(callID string) (PartialCall, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	call, ok := a.calls[callID]
+	if !ok {
+		return PartialCall{}, false
+	}
+	return *call, true
+}
+
+func (a *ArgumentAssembler) callLocked(callID, itemID string) *PartialCall {
+	if call, ok := a.calls[callID]; ok && callID != "" {
+		if call.ItemID == "" && itemID != "" {
+			call.ItemID = itemID
+			a.items[itemID] = call
+		}
+		return call
+	}
+	if call, ok := a.items[itemID]; ok && itemID != "" {
+		if call.CallID == "" && callID != "" {
+			call.CallID = callID
+			a.calls[callID] = call
+		}
+		return call
+	}
+	call := &PartialCall{CallID: callID, ItemID: itemID}
+	if callID != "" {
+		a.calls[callID] = call
+	}
+	if itemID != "" {
+		a.items[itemID] = call
+	}
+	return call
+}
+
+// ParsePartialJSON 解析可能被截断的 JSON 文本：补全未闭合的字符串、数组和对象，
+// 并丢弃末尾不完整的键、字面量或数字，无法解析时返回 nil
+func ParsePartialJSON(s string) any {
+	s = strings.TrimSpace(s)
+	// 末尾没有分隔符的数字可能被截断（如 12 只收到 1），直接丢弃
+	if _, inString, _, ok := scanJSON(s); ok && !inString {
+		s = trimNumber(s)
+	}
+	for end := len(s); end > 0; end-- {
+		candidate, ok := closeJSON(s[:end])
+		if !ok {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal([]byte(candidate), &value); err == nil {
+			return value
+		}
+	}
+	return nil
+}
+
+// scanJSON 扫描 JSON 前缀，返回未闭合的容器以及末尾是否处于字符串或转义中
+func scanJSON(prefix string) (stack []byte, inString, escaped, ok bool) {
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+		case '}', ']':
+			if len(stack) == 0 {
+				return nil, false, false, false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return stack, inString, escaped, true
+}
+
+// closeJSON 为 JSON 前缀补全未闭合的字符串和容器
+func closeJSON(prefix string) (string, bool) {
+	stack, inString, escaped, ok := scanJSON(prefix)
+	if !ok || escaped {
+		return "", false
+	}
+	var b strings.Builder
+	b.WriteString(prefix)
+	if inString {
+		b.WriteByte('"')
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == '{' {
+			b.WriteByte('}')
+		} else {
+			b.WriteByte(']')
+		}
+	}
+	return b.String(), true
+}
+
+// trimNumber 去掉末尾的数字，true、false 等以 e 结尾的字面量保持不变
+func trimNumber(s string) string {
+	i := len(s)
+	for i > 0 && strings.IndexByte("0123456789.eE+-", s[i-1]) >= 0 {
+		i--
+	}
+	if i < len(s) && (s[i] == '-' || (s[i] >= '0' && s[i] <= '9')) {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package toolcall
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func TestParsePartialJSON(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  any
+	}{
+		{``, nil},
+		{`{`, map[string]any{}},
+		{`{"loca`, map[string]any{}},
+		{`{"location":`, map[string]any{}},
+		{`{"location":"Bei`, map[string]any{"location": "Bei"}},
+		{`{"location":"Bei\`, map[string]any{"location": "Bei"}},
+		{`{"location":"北京","days":1`, map[string]any{"location": "北京"}},
+		{`{"location":"北京","days":12,`, map[string]any{"location": "北京", "days": float64(12)}},
+		{`{"ids":[1,2`, map[string]any{"ids": []any{float64(1)}}},
+		{`{"ok":true`, map[string]any{"ok": true}},
+		{`{"n":-1.5e3}`, map[string]any{"n": float64(-1500)}},
+		{`{"location":"北京","ok":tr`, map[string]any{"location": "北京"}},
+		{`{"tags":["a","b`, map[string]any{"tags": []any{"a", "b"}}},
+		{`{"nested":{"x":[1,{"y":"z`, map[string]any{"nested": map[string]any{"x": []any{float64(1), map[string]any{"y": "z"}}}}},
+	} {
+		if got := ParsePartialJSON(tc.input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParsePartialJSON(%q) = %#v, want %#v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestArgumentAssembler(t *testing.T) {
+	var updates []PartialCall
+	assembler := NewArgumentAssembler(func(call PartialCall) {
+		updates = append(updates, call)
+	})
+	assembler.Observe(&events.Event{Type: events.RealtimeServerEventResponseOutputItemAdded, ResponseID: "resp_1", Item: &events.Item{ID: "item_1", Type: events.ItemTypeFunctionCall, Name: "SearchWeather", CallId: "call_1"}})
+	for _, delta := range []string{`{"loc`, `ation":"北`, `京"}`} {
+		assembler.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDelta, ItemID: "item_1", CallID: "call_1", Delta: delta})
+	}
+	assembler.Observe(&events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ItemID: "item_1", CallID: "call_1", Name: "SearchWeather", Arguments: `{"location":"北京"}`})
+
+	if len(updates) != 4 {
+		t.Fatalf("expected 4 updates, got %d", len(updates))
+	}
+	if second := updates[1]; second.Name != "SearchWeather" || second.Parsed["location"] != "北" || second.Done {
+		t.Fatalf("unexpected partial update: %+v", second)
+	}
+	if last := updates[3]; !last.Done || last.Arguments != `{"location":"北京"}` || last.ResponseID != "resp_1" {
+		t.Fatalf("unexpected final update: %+v", last)
+	}
+
+	assembler.Observe(&events.Event{Type: events.RealtimeServerEventResponseDone})
+	if _, ok := assembler.Call("call_1"); ok {
+		t.Fatalf("completed calls should be cleaned up after response.done")
+	}
+}
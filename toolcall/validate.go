@@ -0,0 +1,309 @@
+package toolcall
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// FieldError 一个参数字段的校验错误，Path 为以 . 分隔的字段路径，数组下标以 [i] 表示
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError 函数调用参数不符合工具声明的 schema
+type ValidationError struct {
+	Tool   string       `json:"tool,omitempty"`
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		if fe.Path == "" {
+			messages = append(messages, fe.Message)
+		} else {
+			messages = append(messages, fe.Path+": "+fe.Message)
+		}
+	}
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.Tool, strings.Join(messages, "; "))
+}
+
+// Validate 按工具的参数定义校验函数调用参数，校验失败时返回 *ValidationError
+func Validate(parameters events.ToolParameters, arguments string) error {
+	if strings.TrimSpace(arguments) == "" {
+		arguments = "{}"
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(arguments)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Errors: []FieldError{{Message: fmt.Sprintf("arguments is not valid JSON: %v", err)}}}
+	}
+	v := &validator{}
+	v.validate("", parametersSchema(parameters), value)
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+func parametersSchema(p events.ToolParameters) *events.ToolProperty {
+	return &events.ToolProperty{
+		Type:                 p.Type,
+		Description:          p.Description,
+		Properties:           p.Properties,
+		Required:             p.Required,
+		AdditionalProperties: p.AdditionalProperties,
+		OneOf:                p.OneOf,
+		AnyOf:                p.AnyOf,
+		AllOf:                p.AllOf,
+	}
+}
+
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errors = append(v.errors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(path string, schema *events.ToolProperty, value any) {
+	if schema == nil {
+		return
+	}
+	if schema.Type != "" && !matchesType(schema.Type, value) {
+		v.fail(path, "expected %s, got %s", schema.Type, typeName(value))
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		v.fail(path, "must be one of %s", enumString(schema.Enum))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(path, schema, val)
+	case []any:
+		if schema.MinItems != nil && len(val) < *schema.MinItems {
+			v.fail(path, "must contain at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(val) > *schema.MaxItems {
+			v.fail(path, "must contain at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range val {
+				v.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(val)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			v.fail(path, "must be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			v.fail(path, "must be at most %d characters", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(val) {
+				v.fail(path, "must match pattern %s", schema.Pattern)
+			}
+		}
+	case json.Number:
+		n, _ := val.Float64()
+		if schema.Minimum != nil && n < *schema.Minimum {
+			v.fail(path, "must be >= %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && n > *schema.Maximum {
+			v.fail(path, "must be <= %v", *schema.Maximum)
+		}
+		if schema.ExclusiveMinimum != nil && n <= *schema.ExclusiveMinimum {
+			v.fail(path, "must be > %v", *schema.ExclusiveMinimum)
+		}
+		if schema.ExclusiveMaximum != nil && n >= *schema.ExclusiveMaximum {
+			v.fail(path, "must be < %v", *schema.ExclusiveMaximum)
+		}
+	}
+
+	for i := range schema.AllOf {
+		v.validate(path, &schema.AllOf[i], value)
+	}
+	if len(schema.AnyOf) > 0 && v.countMatches(path, schema.AnyOf, value) == 0 {
+		v.fail(path, "must match at least one schema in anyOf")
+	}
+	if len(schema.OneOf) > 0 {
+		if n := v.countMatches(path, schema.OneOf, value); n != 1 {
+			v.fail(path, "must match exactly one schema in oneOf, matched %d", n)
+		}
+	}
+}
+
+func (v *validator) validateObject(path string, schema *events.ToolProperty, val map[string]any) {
+	for _, name := range schema.Required {
+		if _, ok := val[name]; !ok {
+			v.fail(joinPath(path, name), "is required")
+		}
+	}
+	keys := make([]string, 0, len(val))
+	for k := range val {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if property, ok := schema.Properties[k]; ok {
+			v.validate(joinPath(path, k), &property, val[k])
+			continue
+		}
+		if ap := schema.AdditionalProperties; ap != nil {
+			if ap.Schema != nil {
+				v.validate(joinPath(path, k), ap.Schema, val[k])
+			} else if !ap.Allowed {
+				v.fail(joinPath(path, k), "is not allowed")
+			}
+		}
+	}
+}
+
+func (v *validator) countMatches(path string, schemas []events.ToolProperty, value any) int {
+	matches := 0
+	for i := range schemas {
+		sub := &validator{}
+		sub.validate(path, &schemas[i], value)
+		if len(sub.errors) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func matchesType(typ string, value any) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		if _, err := n.Int64(); err == nil {
+			return true
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	return true
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum []any, value any) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, e := range enum {
+		if candidate, err := json.Marshal(e); err == nil && bytes.Equal(candidate, data) {
+			return true
+		}
+		// 数值以不同形式书写时按数值比较，例如 1 和 1.0
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				if ef, ok := toFloat(e); ok && ef == f {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func enumString(enum []any) string {
+	data, _ := json.Marshal(enum)
+	return string(data)
+}
+
+// ErrorOutput 将错误编码为发回给模型的 JSON 结果，参数校验错误会带上字段明细便于模型修正后重试，
+// 被中间件拒绝的调用会带上拒绝原因
+func ErrorOutput(err error) string {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		data, _ := json.Marshal(map[string]any{
+			"error":   "invalid_arguments",
+			"message": validationErr.Error(),
+			"details": validationErr.Errors,
+		})
+		return string(data)
+	}
+	var deniedErr *DeniedError
+	if errors.As(err, &deniedErr) {
+		data, _ := json.Marshal(map[string]string{
+			"error":   "denied",
+			"message": deniedErr.Reason,
+		})
+		return string(data)
+	}
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
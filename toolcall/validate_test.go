@@ -0,0 +1,70 @@
+package toolcall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func TestValidate(t *testing.T) {
+	var parameters events.ToolParameters
+	if err := json.Unmarshal([]byte(`{"type":"object","properties":{`+
+		`"location":{"type":"string","minLength":1},`+
+		`"days":{"type":"integer","minimum":1,"maximum":7},`+
+		`"unit":{"type":"string","enum":["celsius","fahrenheit"]},`+
+		`"tags":{"type":"array","items":{"type":"string"}}},`+
+		`"required":["location"],"additionalProperties":false}`), &parameters); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		arguments string
+		want      []FieldError
+	}{
+		{`{"location":"北京","days":3,"unit":"celsius","tags":["a"]}`, nil},
+		{`{"location":"北京","days":3.0}`, nil},
+		{`{"localtion":"北京"}`, []FieldError{{"location", "is required"}, {"localtion", "is not allowed"}}},
+		{`{"location":"北京","days":1.5,"unit":"kelvin","tags":["a",1]}`, []FieldError{
+			{"days", "expected integer, got number"},
+			{"tags[1]", "expected string, got number"},
+			{"unit", `must be one of ["celsius","fahrenheit"]`},
+		}},
+		{`{"location":"","days":9}`, []FieldError{{"days", "must be <= 7"}, {"location", "must be at least 1 characters"}}},
+		{`[1]`, []FieldError{{"", "expected object, got array"}}},
+	} {
+		err := Validate(parameters, tc.arguments)
+		if tc.want == nil {
+			if err != nil {
+				t.Errorf("Validate(%s) = %v, want nil", tc.arguments, err)
+			}
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !reflect.DeepEqual(validationErr.Errors, tc.want) {
+			t.Errorf("Validate(%s) = %v, want %v", tc.arguments, err, tc.want)
+		}
+	}
+}
+
+func TestInvokeRejectsInvalidArguments(t *testing.T) {
+	registry := NewRegistry()
+	called := false
+	if err := RegisterTyped(registry, "SearchWeather", "查询指定城市的天气", func(ctx context.Context, args weatherArgs) (string, error) {
+		called = true
+		return "晴", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := registry.Invoke(context.Background(), "SearchWeather", `{"localtion":"北京"}`)
+	if err == nil || called {
+		t.Fatalf("invalid arguments should not reach the handler, err: %v", err)
+	}
+	var output map[string]any
+	if err = json.Unmarshal([]byte(ErrorOutput(err)), &output); err != nil || output["error"] != "invalid_arguments" || output["details"] == nil {
+		t.Fatalf("unexpected error output: %v, %v", output, err)
+	}
+}
@@ -3,7 +3,6 @@ package tools
 import (
 	"bytes"
 	"encoding/base64"
-	"encoding/binary"
 	"fmt"
 	"io"
 	"log"
@@ -56,6 +55,7 @@ func ConcatWavBytes(wavBytes [][]byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer os.Remove(tempFile.Name())
 	defer tempFile.Close() // 确保文件会被关闭
 
 	encoder := wav.NewEncoder(tempFile, params.SampleRate, bitDepth, params.NumChannels, 1)
@@ -86,43 +86,10 @@ func ConcatWavBytes(wavBytes [][]byte) ([]byte, error) {
 // numChannels: 声道数 (1: 单声道, 2: 双声道)
 // bitDepth: 位深度 (通常是 16)
 func Pcm2Wav(pcmBytes []byte, sampleRate, numChannels, bitDepth int) ([]byte, error) {
-	// WAV 文件头大小为 44 字节
-	headerSize := 44
-	fileSize := len(pcmBytes) + headerSize
-
-	// 创建包含文件头的字节切片
-	wavData := make([]byte, fileSize)
-
-	// 1. RIFF 头
-	copy(wavData[0:4], []byte("RIFF"))
-	// 2. 文件大小 (文件总字节数 - 8)
-	binary.LittleEndian.PutUint32(wavData[4:8], uint32(fileSize-8))
-	// 3. WAVE 标记
-	copy(wavData[8:12], []byte("WAVE"))
-	// 4. fmt 子块
-	copy(wavData[12:16], []byte("fmt "))
-	// 5. fmt 子块大小 (16 表示 PCM 格式)
-	binary.LittleEndian.PutUint32(wavData[16:20], 16)
-	// 6. 音频格式 (1 表示 PCM)
-	binary.LittleEndian.PutUint16(wavData[20:22], 1)
-	// 7. 声道数
-	binary.LittleEndian.PutUint16(wavData[22:24], uint16(numChannels))
-	// 8. 采样率
-	binary.LittleEndian.PutUint32(wavData[24:28], uint32(sampleRate))
-	// 9. 字节率 (采样率 * 通道数 * 位深度 / 8)
-	binary.LittleEndian.PutUint32(wavData[28:32], uint32(sampleRate*numChannels*bitDepth/8))
-	// 10. 数据块对齐 (通道数 * 位深度 / 8)
-	binary.LittleEndian.PutUint16(wavData[32:34], uint16(numChannels*bitDepth/8))
-	// 11. 位深度
-	binary.LittleEndian.PutUint16(wavData[34:36], uint16(bitDepth))
-	// 12. data 子块
-	copy(wavData[36:40], []byte("data"))
-	// 13. 数据大小
-	binary.LittleEndian.PutUint32(wavData[40:44], uint32(len(pcmBytes)))
-
-	// 复制 PCM 数据
-	copy(wavData[44:], pcmBytes)
-
+	// 数据块大小为奇数时末尾补一个填充字节
+	wavData := make([]byte, WavHeaderSize+len(pcmBytes)+len(pcmBytes)%2)
+	putWavHeader(wavData, uint32(len(pcmBytes)), sampleRate, numChannels, bitDepth)
+	copy(wavData[WavHeaderSize:], pcmBytes)
 	return wavData, nil
 }
 
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// WavHeaderSize PCM WAV 文件头的大小
+const WavHeaderSize = 44
+
+// putWavHeader 写入 44 字节的 PCM WAV 文件头，dataSize 为 PCM 数据的字节数
+func putWavHeader(header []byte, dataSize uint32, sampleRate, numChannels, bitDepth int) {
+	riffSize := dataSize
+	if riffSize <= math.MaxUint32-(WavHeaderSize-8) {
+		riffSize += WavHeaderSize - 8
+	}
+	// 数据块大小为奇数时末尾有一个填充字节，计入 RIFF 大小
+	if dataSize%2 == 1 && riffSize < math.MaxUint32 {
+		riffSize++
+	}
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], riffSize)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1)
+	binary.LittleEndian.PutUint16(header[22:24], uint16(numChannels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRate*numChannels*bitDepth/8))
+	binary.LittleEndian.PutUint16(header[32:34], uint16(numChannels*bitDepth/8))
+	binary.LittleEndian.PutUint16(header[34:36], uint16(bitDepth))
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], dataSize)
+}
+
+// WavWriter 将 PCM 数据增量写入 WAV，内存占用与音频时长无关。
+// 底层支持 Seek 时，Close 会回到开头补写文件头中的长度；不支持时（如管道、HTTP 响应）文件头中的长度为
+// 0xFFFFFFFF，多数播放器会读到流结束，也可以在 Close 后通过 Header 取得最终文件头自行补写
+type WavWriter struct {
+	w           io.Writer
+	seeker      io.Seeker
+	start       int64
+	sampleRate  int
+	numChannels int
+	bitDepth    int
+	dataSize    int64
+	src, buf    []byte
+	closed      bool
+}
+
+// NewWavWriter 在 w 的当前位置写入文件头并返回 WavWriter，Close 不会关闭 w。
+// 文件不能以 O_APPEND 方式打开，否则补写的文件头会被追加到末尾
+func NewWavWriter(w io.Writer, sampleRate, numChannels, bitDepth int) (*WavWriter, error) {
+	if sampleRate <= 0 || numChannels <= 0 || bitDepth <= 0 || bitDepth%8 != 0 {
+		return nil, fmt.Errorf("invalid wav format: sample rate %d, channels %d, bit depth %d", sampleRate, numChannels, bitDepth)
+	}
+	ww := &WavWriter{w: w, sampleRate: sampleRate, numChannels: numChannels, bitDepth: bitDepth}
+	if seeker, ok := w.(io.Seeker); ok {
+		// *os.File 指向管道或终端时 Seek 会失败，按不可 Seek 处理
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			ww.seeker, ww.start = seeker, start
+		}
+	}
+	var header [WavHeaderSize]byte
+	putWavHeader(header[:], math.MaxUint32, sampleRate, numChannels, bitDepth)
+	if _, err := w.Write(header[:]); err != nil {
+		return nil, err
+	}
+	return ww, nil
+}
+
+// Write 追加一段 PCM 数据
+func (w *WavWriter) Write(pcm []byte) (int, error) {
+	if w.closed {
+		return 0, fmt.Errorf("wav writer is closed")
+	}
+	if w.dataSize+int64(len(pcm)) > math.MaxUint32-WavHeaderSize {
+		return 0, fmt.Errorf("wav data exceeds 4GB")
+	}
+	n, err := w.w.Write(pcm)
+	w.dataSize += int64(n)
+	return n, err
+}
+
+// WriteBase64 解码 base64 编码的 PCM 数据（如 response.audio.delta）并追加，解码缓冲区会被复用
+func (w *WavWriter) WriteBase64(data string) error {
+	n := base64.StdEncoding.DecodedLen(len(data))
+	if cap(w.buf) < n {
+		w.buf = make([]byte, n)
+	}
+	w.src = append(w.src[:0], data...)
+	n, err := base64.StdEncoding.Decode(w.buf[:n], w.src)
+	if err != nil {
+		return fmt.Errorf("decode audio failed: %w", err)
+	}
+	_, err = w.Write(w.buf[:n])
+	return err
+}
+
+// DataSize 已写入的 PCM 数据字节数
+func (w *WavWriter) DataSize() int64 {
+	return w.dataSize
+}
+
+// Duration 已写入音频的时长
+func (w *WavWriter) Duration() time.Duration {
+	bytesPerSecond := int64(w.sampleRate * w.numChannels * w.bitDepth / 8)
+	return time.Duration(w.dataSize * int64(time.Second) / bytesPerSecond)
+}
+
+// Header 返回按当前已写入数据长度生成的文件头，不可 Seek 的输出可在之后用它覆盖开头的 44 字节
+func (w *WavWriter) Header() []byte {
+	header := make([]byte, WavHeaderSize)
+	putWavHeader(header, uint32(w.dataSize), w.sampleRate, w.numChannels, w.bitDepth)
+	return header
+}
+
+// Close 补齐数据块的填充字节，并在可 Seek 时补写文件头，之后写入位置回到数据末尾
+func (w *WavWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.dataSize%2 == 1 {
+		if _, err := w.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	if w.seeker == nil {
+		return nil
+	}
+	end, err := w.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = w.seeker.Seek(w.start, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err = w.w.Write(w.Header()); err != nil {
+		return err
+	}
+	_, err = w.seeker.Seek(end, io.SeekStart)
+	return err
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-audio/wav"
+)
+
+func TestWavWriterSeekable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer, err := NewWavWriter(file, 24000, 1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pcm []byte
+	for i := 0; i < 10; i++ {
+		chunk := bytes.Repeat([]byte{byte(i), 0}, 2400)
+		pcm = append(pcm, chunk...)
+		if i%2 == 0 {
+			_, err = writer.Write(chunk)
+		} else {
+			err = writer.WriteBase64(base64.StdEncoding.EncodeToString(chunk))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if writer.Duration() != time.Second {
+		t.Errorf("unexpected duration %v", writer.Duration())
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := Pcm2Wav(pcm, 24000, 1, 16)
+	if !bytes.Equal(data, want) {
+		t.Fatalf("output differs from Pcm2Wav, got %d bytes, want %d", len(data), len(want))
+	}
+	decoder := wav.NewDecoder(bytes.NewReader(data))
+	buf, err := decoder.FullPCMBuffer()
+	if err != nil || len(buf.Data) != len(pcm)/2 || buf.Format.SampleRate != 24000 {
+		t.Fatalf("decode failed: %v", err)
+	}
+}
+
+func TestWavWriterStreaming(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewWavWriter(&out, 16000, 1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = writer.Write([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := out.Bytes()
+	if size := binary.LittleEndian.Uint32(data[40:44]); size != math.MaxUint32 {
+		t.Errorf("streaming header should use unknown data size, got %d", size)
+	}
+	if len(data) != WavHeaderSize+4 {
+		t.Errorf("odd data should be padded, got %d bytes", len(data))
+	}
+	copy(data, writer.Header())
+	want, _ := Pcm2Wav([]byte{1, 2, 3}, 16000, 1, 16)
+	if !bytes.Equal(data, want) {
+		t.Errorf("patched output mismatch:\n got %v\nwant %v", data, want)
+	}
+	if _, err = writer.Write([]byte{0}); err == nil {
+		t.Error("write after close should fail")
+	}
+}
@@ -0,0 +1,142 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// 没有时长信息的字幕条目使用的最短显示时间
+const minCueDuration = time.Second
+
+var roleLabels = map[events.ItemRole]string{
+	events.ItemRoleUser:      "用户",
+	events.ItemRoleAssistant: "助手",
+	events.ItemRoleSystem:    "系统",
+}
+
+func roleLabel(role events.ItemRole) string {
+	if label, ok := roleLabels[role]; ok {
+		return label
+	}
+	return string(role)
+}
+
+// WriteMarkdown 导出为 Markdown 格式
+func (t *Transcript) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# 对话记录\n\n")
+	if t.SessionID != "" {
+		fmt.Fprintf(&b, "- 会话: `%s`\n", t.SessionID)
+	}
+	if !t.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "- 开始时间: %s\n", t.StartedAt.Format(time.RFC3339))
+	}
+	b.WriteString("\n")
+	for _, e := range t.Entries {
+		stamp := formatTimestamp(e.Start, '.')
+		switch e.Kind {
+		case EntryKindMessage:
+			fmt.Fprintf(&b, "**[%s] %s**: %s\n\n", stamp, roleLabel(e.Role), e.Text)
+		case EntryKindFunctionCall:
+			fmt.Fprintf(&b, "**[%s] 函数调用** `%s` (call_id: `%s`)\n\n```json\n%s\n```\n\n", stamp, e.Name, e.CallID, e.Arguments)
+		case EntryKindFunctionCallOutput:
+			fmt.Fprintf(&b, "**[%s] 函数结果** `%s` (call_id: `%s`)\n\n```\n%s\n```\n\n", stamp, e.Name, e.CallID, e.Output)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type jsonEntry struct {
+	Entry
+	StartMS int64 `json:"start_ms"`
+	EndMS   int64 `json:"end_ms"`
+}
+
+// WriteJSON 导出为结构化 JSON，时间偏移以毫秒表示
+func (t *Transcript) WriteJSON(w io.Writer) error {
+	out := struct {
+		SessionID string      `json:"session_id,omitempty"`
+		StartedAt time.Time   `json:"started_at"`
+		Entries   []jsonEntry `json:"entries"`
+	}{SessionID: t.SessionID, StartedAt: t.StartedAt, Entries: make([]jsonEntry, 0, len(t.Entries))}
+	for _, e := range t.Entries {
+		out.Entries = append(out.Entries, jsonEntry{Entry: e, StartMS: e.Start.Milliseconds(), EndMS: e.End.Milliseconds()})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(out)
+}
+
+// WriteSRT 导出为 SRT 字幕，仅包含对话消息
+func (t *Transcript) WriteSRT(w io.Writer) error {
+	var b strings.Builder
+	for i, cue := range t.cues() {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s: %s\n\n", i+1, formatTimestamp(cue.Start, ','), formatTimestamp(cue.End, ','), roleLabel(cue.Role), srtText(cueText(cue.Text)))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteWebVTT 导出为 WebVTT 字幕，说话人以 voice 标签标注
+func (t *Transcript) WriteWebVTT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range t.cues() {
+		fmt.Fprintf(&b, "%s --> %s\n<v %s>%s\n\n", formatTimestamp(cue.Start, '.'), formatTimestamp(cue.End, '.'), vttEscaper.Replace(roleLabel(cue.Role)), vttEscaper.Replace(cueText(cue.Text)))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (t *Transcript) cues() []Entry {
+	cues := make([]Entry, 0, len(t.Entries))
+	for _, e := range t.Entries {
+		if e.Kind != EntryKindMessage || e.Text == "" {
+			continue
+		}
+		if e.End-e.Start < minCueDuration {
+			e.End = e.Start + minCueDuration
+		}
+		cues = append(cues, e)
+	}
+	return cues
+}
+
+// srtText 替换字幕文本中的 -->，避免被解析为时间轴
+func srtText(text string) string {
+	for strings.Contains(text, "-->") {
+		text = strings.ReplaceAll(text, "-->", "->")
+	}
+	return text
+}
+
+// vttEscaper WebVTT 字幕文本中的 &、<、> 需转义为字符实体
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// cueText 去掉字幕文本中的空行，空行在 SRT 和 WebVTT 中都表示字幕条目结束
+func cueText(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(strings.ReplaceAll(text, "\r", "\n"), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func formatTimestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
@@ -0,0 +1,246 @@
+package transcript
+
+import (
+	"encoding/base64"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+type EntryKind string
+
+const (
+	EntryKindMessage            EntryKind = "message"
+	EntryKindFunctionCall       EntryKind = "function_call"
+	EntryKindFunctionCallOutput EntryKind = "function_call_output"
+)
+
+// Entry 对话记录中的一条，Start/End 为相对会话开始的偏移。用户语音使用服务端 VAD 事件中输入音频的
+// audio_start_ms/audio_end_ms，缺少时和其他条目一样使用收到对应事件的时间
+type Entry struct {
+	Kind       EntryKind       `json:"kind"`
+	Role       events.ItemRole `json:"role,omitempty"`
+	ItemID     string          `json:"item_id,omitempty"`
+	ResponseID string          `json:"response_id,omitempty"`
+	Text       string          `json:"text,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	CallID     string          `json:"call_id,omitempty"`
+	Arguments  string          `json:"arguments,omitempty"`
+	Output     string          `json:"output,omitempty"`
+	Start      time.Duration   `json:"-"`
+	End        time.Duration   `json:"-"`
+	// 是否有对应的音频，助手音频的 End 按音频长度推算
+	Audio bool `json:"audio"`
+
+	done       bool
+	audioBytes int
+	audioStart time.Duration
+	// seen 首次收到该条目事件的时间，用于按对话顺序排列
+	seen time.Duration
+}
+
+// Transcript 一次会话的完整对话记录
+type Transcript struct {
+	SessionID string    `json:"session_id,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Recorder 监听服务端事件并生成对话记录，可直接在 onReceived 回调中调用 Observe
+type Recorder struct {
+	mu             sync.Mutex
+	sessionID      string
+	startedAt      time.Time
+	entries        []*Entry
+	items          map[string]*Entry
+	calls          map[string]*Entry
+	audioBytesPerS int
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		items: make(map[string]*Entry),
+		calls: make(map[string]*Entry),
+		// 默认输出为 24kHz 单声道 16bit PCM
+		audioBytesPerS: 24000 * 2,
+	}
+}
+
+// SetOutputAudioFormat 设置输出音频格式，用于根据音频数据长度计算时长
+func (r *Recorder) SetOutputAudioFormat(sampleRate, numChannels, bitDepth int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.audioBytesPerS = sampleRate * numChannels * bitDepth / 8
+}
+
+// Observe 以当前时间记录一个事件
+func (r *Recorder) Observe(event *events.Event) {
+	r.ObserveAt(event, time.Now())
+}
+
+// ObserveAt 以指定时间记录一个事件，用于回放录制的事件流
+func (r *Recorder) ObserveAt(event *events.Event, at time.Time) {
+	if event == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.startedAt.IsZero() {
+		r.startedAt = at
+	}
+	offset := at.Sub(r.startedAt)
+
+	switch event.Type {
+	case events.RealtimeServerEventSessionCreated:
+		if event.Session != nil {
+			r.sessionID = event.Session.ID
+		}
+	// 用户语音对齐到输入音频的时间轴，服务端没有返回 audio_start_ms/audio_end_ms 时使用收到事件的时间
+	case events.RealtimeServerEventInputAudioBufferSpeechStarted:
+		e := r.itemLocked(event.ItemID, events.ItemRoleUser, offset)
+		start := offset
+		if event.AudioStartMS > 0 {
+			start = time.Duration(event.AudioStartMS) * time.Millisecond
+		}
+		e.Start, e.End, e.Audio = start, start, true
+	case events.RealtimeServerEventInputAudioBufferSpeechStopped:
+		e := r.itemLocked(event.ItemID, events.ItemRoleUser, offset)
+		e.End, e.Audio = offset, true
+		if event.AudioEndMS > 0 {
+			e.End = time.Duration(event.AudioEndMS) * time.Millisecond
+		}
+	case events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted:
+		e := r.itemLocked(event.ItemID, events.ItemRoleUser, offset)
+		if event.Transcript != nil {
+			e.Text = *event.Transcript
+		}
+		if !e.Audio {
+			e.End = offset
+		}
+		e.done = true
+	case events.RealtimeServerEventConversationItemCreated:
+		if event.Item == nil {
+			return
+		}
+		switch event.Item.Type {
+		case events.ItemTypeFunctionCallOutput:
+			e := r.appendLocked(&Entry{Kind: EntryKindFunctionCallOutput, ItemID: event.Item.ID, CallID: event.Item.CallId, Start: offset, End: offset, done: true})
+			if event.Item.Output != nil {
+				e.Output = *event.Item.Output
+			}
+			if call, ok := r.calls[event.Item.CallId]; ok {
+				e.Name = call.Name
+			}
+		case events.ItemTypeMessage:
+			// 文本输入的用户消息不会有转写事件，直接从 item 中取内容
+			if event.Item.Role == events.ItemRoleAssistant {
+				return
+			}
+			text := contentText(event.Item.Content)
+			if text == "" {
+				return
+			}
+			e := r.itemLocked(event.Item.ID, event.Item.Role, offset)
+			e.Text, e.End, e.done = text, offset, true
+		}
+	case events.RealtimeServerEventResponseAudioDelta:
+		e := r.itemLocked(event.ItemID, events.ItemRoleAssistant, offset)
+		e.ResponseID = event.ResponseID
+		if !e.Audio {
+			e.Audio, e.audioStart = true, offset
+			e.Start = offset
+		}
+		e.audioBytes += base64.StdEncoding.DecodedLen(len(event.Delta))
+		if r.audioBytesPerS > 0 {
+			e.End = e.audioStart + time.Duration(e.audioBytes)*time.Second/time.Duration(r.audioBytesPerS)
+		}
+	case events.RealtimeServerEventResponseAudioTranscriptDelta, events.RealtimeServerEventResponseTextDelta:
+		e := r.itemLocked(event.ItemID, events.ItemRoleAssistant, offset)
+		e.ResponseID = event.ResponseID
+		if !e.done {
+			e.Text += event.Delta
+		}
+		if !e.Audio {
+			e.End = offset
+		}
+	case events.RealtimeServerEventResponseAudioTranscriptDone:
+		e := r.itemLocked(event.ItemID, events.ItemRoleAssistant, offset)
+		e.ResponseID = event.ResponseID
+		if event.Transcript != nil {
+			e.Text = *event.Transcript
+		}
+		e.done = true
+	case events.RealtimeServerEventResponseTextDone:
+		e := r.itemLocked(event.ItemID, events.ItemRoleAssistant, offset)
+		e.ResponseID = event.ResponseID
+		if event.Text != nil {
+			e.Text = *event.Text
+		}
+		if !e.Audio {
+			e.End = offset
+		}
+		e.done = true
+	case events.RealtimeServerEventResponseFunctionCallArgumentsDone:
+		e := r.appendLocked(&Entry{
+			Kind:       EntryKindFunctionCall,
+			Role:       events.ItemRoleAssistant,
+			ItemID:     event.ItemID,
+			ResponseID: event.ResponseID,
+			Name:       event.Name,
+			CallID:     event.CallID,
+			Arguments:  event.Arguments,
+			Start:      offset,
+			End:        offset,
+			done:       true,
+		})
+		r.calls[event.CallID] = e
+	}
+}
+
+// Transcript 返回当前的对话记录，条目按对话顺序（首次收到该条目事件的时间）排列
+func (r *Recorder) Transcript() *Transcript {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := &Transcript{SessionID: r.sessionID, StartedAt: r.startedAt, Entries: make([]Entry, 0, len(r.entries))}
+	for _, e := range r.entries {
+		if e.Kind == EntryKindMessage && e.Text == "" {
+			continue
+		}
+		t.Entries = append(t.Entries, *e)
+	}
+	sort.SliceStable(t.Entries, func(i, j int) bool {
+		return t.Entries[i].seen < t.Entries[j].seen
+	})
+	return t
+}
+
+func (r *Recorder) itemLocked(itemID string, role events.ItemRole, offset time.Duration) *Entry {
+	if e, ok := r.items[itemID]; ok && itemID != "" {
+		return e
+	}
+	e := r.appendLocked(&Entry{Kind: EntryKindMessage, Role: role, ItemID: itemID, Start: offset, End: offset})
+	if itemID != "" {
+		r.items[itemID] = e
+	}
+	return e
+}
+
+func (r *Recorder) appendLocked(e *Entry) *Entry {
+	e.seen = e.Start
+	r.entries = append(r.entries, e)
+	return e
+}
+
+func contentText(contents []events.Content) string {
+	text := ""
+	for _, c := range contents {
+		if c.Text != nil {
+			text += *c.Text
+		} else if c.Transcript != nil {
+			text += *c.Transcript
+		}
+	}
+	return text
+}
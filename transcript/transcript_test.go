@@ -0,0 +1,145 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestRecorderExport(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	audio := base64.StdEncoding.EncodeToString(make([]byte, 48000)) // 1s of 24kHz 16bit mono
+
+	r := NewRecorder()
+	for _, step := range []struct {
+		ms    int
+		event *events.Event
+	}{
+		{0, &events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_1"}}},
+		{500, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "u1", AudioStartMS: 400}},
+		{2000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "u1", AudioEndMS: 1900}},
+		{2500, &events.Event{Type: events.RealtimeServerEventResponseFunctionCallArgumentsDone, ItemID: "f1", CallID: "call_1", Name: "SearchWeather", Arguments: `{"location":"北京"}`}},
+		{2600, &events.Event{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u1", Transcript: strPtr("北京天气怎么样")}},
+		{2700, &events.Event{Type: events.RealtimeServerEventConversationItemCreated, Item: &events.Item{ID: "o1", Type: events.ItemTypeFunctionCallOutput, CallId: "call_1", Output: strPtr("晴")}}},
+		{3000, &events.Event{Type: events.RealtimeServerEventResponseAudioDelta, ItemID: "a1", Delta: audio}},
+		{3100, &events.Event{Type: events.RealtimeServerEventResponseAudioTranscriptDelta, ItemID: "a1", Delta: "北京"}},
+		{3200, &events.Event{Type: events.RealtimeServerEventResponseAudioDelta, ItemID: "a1", Delta: audio}},
+		{3300, &events.Event{Type: events.RealtimeServerEventResponseAudioTranscriptDone, ItemID: "a1", Transcript: strPtr("北京今天晴")}},
+	} {
+		r.ObserveAt(step.event, at(step.ms))
+	}
+
+	tr := r.Transcript()
+	if tr.SessionID != "sess_1" || len(tr.Entries) != 4 {
+		t.Fatalf("unexpected transcript: %+v", tr)
+	}
+	if user := tr.Entries[0]; user.Text != "北京天气怎么样" || user.Start != 400*time.Millisecond || user.End != 1900*time.Millisecond {
+		t.Fatalf("unexpected user entry: %+v", user)
+	}
+	if output := tr.Entries[2]; output.Kind != EntryKindFunctionCallOutput || output.Name != "SearchWeather" {
+		t.Fatalf("unexpected function output entry: %+v", output)
+	}
+	if assistant := tr.Entries[3]; assistant.Text != "北京今天晴" || assistant.End != 5*time.Second {
+		t.Fatalf("unexpected assistant entry: %+v", assistant)
+	}
+
+	var srt bytes.Buffer
+	if err := tr.WriteSRT(&srt); err != nil {
+		t.Fatal(err)
+	}
+	want := "1\n00:00:00,400 --> 00:00:01,900\n用户: 北京天气怎么样\n\n2\n00:00:03,000 --> 00:00:05,000\n助手: 北京今天晴\n\n"
+	if srt.String() != want {
+		t.Fatalf("unexpected srt:\n%s", srt.String())
+	}
+
+	var vtt, md, js bytes.Buffer
+	if err := tr.WriteWebVTT(&vtt); err != nil || !strings.HasPrefix(vtt.String(), "WEBVTT\n\n00:00:00.400 --> 00:00:01.900\n<v 用户>") {
+		t.Fatalf("unexpected webvtt: %v\n%s", err, vtt.String())
+	}
+	if err := tr.WriteMarkdown(&md); err != nil || !strings.Contains(md.String(), "`SearchWeather`") {
+		t.Fatalf("unexpected markdown: %v\n%s", err, md.String())
+	}
+	if err := tr.WriteJSON(&js); err != nil || !strings.Contains(js.String(), `"end_ms": 5000`) {
+		t.Fatalf("unexpected json: %v\n%s", err, js.String())
+	}
+}
+
+// TestRecorderInterleavedTurns 客户端在两轮之间暂停推流时，用户语音仍对齐到输入音频的时间轴，条目按对话顺序排列；
+// 服务端没有返回音频时间时使用收到事件的时间
+func TestRecorderInterleavedTurns(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	audio := base64.StdEncoding.EncodeToString(make([]byte, 48000))
+
+	r := NewRecorder()
+	for _, step := range []struct {
+		ms    int
+		event *events.Event
+	}{
+		{0, &events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "sess_1"}}},
+		{1000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "u1", AudioStartMS: 900}},
+		{2000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "u1", AudioEndMS: 1900}},
+		{2100, &events.Event{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u1", Transcript: strPtr("你好")}},
+		{2500, &events.Event{Type: events.RealtimeServerEventResponseAudioDelta, ItemID: "a1", Delta: audio}},
+		{2600, &events.Event{Type: events.RealtimeServerEventResponseAudioTranscriptDone, ItemID: "a1", Transcript: strPtr("你好，有什么可以帮你")}},
+		{10000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "u2", AudioStartMS: 2200}},
+		{11000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "u2", AudioEndMS: 3200}},
+		{11100, &events.Event{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u2", Transcript: strPtr("讲个笑话")}},
+		{11500, &events.Event{Type: events.RealtimeServerEventResponseAudioDelta, ItemID: "a2", Delta: audio}},
+		{11600, &events.Event{Type: events.RealtimeServerEventResponseAudioTranscriptDone, ItemID: "a2", Transcript: strPtr("好的")}},
+		{20000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStarted, ItemID: "u3"}},
+		{21000, &events.Event{Type: events.RealtimeServerEventInputAudioBufferSpeechStopped, ItemID: "u3"}},
+		{21100, &events.Event{Type: events.RealtimeServerEventConversationItemInputAudioTranscriptionCompleted, ItemID: "u3", Transcript: strPtr("再见")}},
+	} {
+		r.ObserveAt(step.event, at(step.ms))
+	}
+
+	tr := r.Transcript()
+	want := []string{"u1", "a1", "u2", "a2", "u3"}
+	if len(tr.Entries) != len(want) {
+		t.Fatalf("unexpected entries: %+v", tr.Entries)
+	}
+	for i, e := range tr.Entries {
+		if e.ItemID != want[i] {
+			t.Fatalf("entry %d: got %s, want %s", i, e.ItemID, want[i])
+		}
+	}
+	if u2 := tr.Entries[2]; u2.Start != 2200*time.Millisecond || u2.End != 3200*time.Millisecond {
+		t.Errorf("unexpected second user entry: %+v", u2)
+	}
+	if u3 := tr.Entries[4]; u3.Start != 20*time.Second || u3.End != 21*time.Second {
+		t.Errorf("user entry without audio offsets should use receive time: %+v", u3)
+	}
+}
+
+func TestSubtitleEscaping(t *testing.T) {
+	tr := &Transcript{Entries: []Entry{
+		{Kind: EntryKindMessage, Role: events.ItemRoleAssistant, Text: "a ---> b\n\n<b>c</b> & d", End: time.Second},
+	}}
+	var srt, vtt bytes.Buffer
+	if err := tr.WriteSRT(&srt); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:00,000 --> 00:00:01,000\n助手: a -> b\n<b>c</b> & d\n\n"; srt.String() != want {
+		t.Errorf("unexpected srt:\n%q", srt.String())
+	}
+	if err := tr.WriteWebVTT(&vtt); err != nil {
+		t.Fatal(err)
+	}
+	if want := "WEBVTT\n\n00:00:00.000 --> 00:00:01.000\n<v 助手>a ---&gt; b\n&lt;b&gt;c&lt;/b&gt; &amp; d\n\n"; vtt.String() != want {
+		t.Errorf("unexpected webvtt:\n%q", vtt.String())
+	}
+}
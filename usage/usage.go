@@ -0,0 +1,292 @@
+package usage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+// Tokens 按输入/输出、文本/音频拆分的 token 计数
+type Tokens struct {
+	InputText   int64 `json:"input_text"`
+	InputAudio  int64 `json:"input_audio"`
+	OutputText  int64 `json:"output_text"`
+	OutputAudio int64 `json:"output_audio"`
+}
+
+// FromUsage 将 response.done 中的 Usage 转换为 Tokens，未细分的部分计入文本
+func FromUsage(u *events.Usage) Tokens {
+	if u == nil {
+		return Tokens{}
+	}
+	inputAudio, outputAudio := int64(u.InputTokenDetails.AudioTokens), int64(u.OutputTokenDetails.AudioTokens)
+	return Tokens{
+		InputText:   u.InputTokens - inputAudio,
+		InputAudio:  inputAudio,
+		OutputText:  u.OutputTokens - outputAudio,
+		OutputAudio: outputAudio,
+	}
+}
+
+func (t *Tokens) Add(o Tokens) {
+	t.InputText += o.InputText
+	t.InputAudio += o.InputAudio
+	t.OutputText += o.OutputText
+	t.OutputAudio += o.OutputAudio
+}
+
+func (t Tokens) Input() int64 {
+	return t.InputText + t.InputAudio
+}
+
+func (t Tokens) Output() int64 {
+	return t.OutputText + t.OutputAudio
+}
+
+func (t Tokens) Total() int64 {
+	return t.Input() + t.Output()
+}
+
+// PriceTable 计价表，根据模型和 token 数估算费用
+type PriceTable interface {
+	Cost(model string, tokens Tokens) float64
+}
+
+// Prices 每百万 token 的单价
+type Prices struct {
+	InputText   float64 `json:"input_text"`
+	InputAudio  float64 `json:"input_audio"`
+	OutputText  float64 `json:"output_text"`
+	OutputAudio float64 `json:"output_audio"`
+}
+
+// StaticPriceTable 按模型名配置的静态计价表，key 为空字符串的条目作为默认价格
+type StaticPriceTable map[string]Prices
+
+func (p StaticPriceTable) Cost(model string, tokens Tokens) float64 {
+	prices, ok := p[model]
+	if !ok {
+		if prices, ok = p[""]; !ok {
+			return 0
+		}
+	}
+	return (float64(tokens.InputText)*prices.InputText +
+		float64(tokens.InputAudio)*prices.InputAudio +
+		float64(tokens.OutputText)*prices.OutputText +
+		float64(tokens.OutputAudio)*prices.OutputAudio) / 1e6
+}
+
+// BudgetAction 超出预算后客户端的处理方式
+type BudgetAction int
+
+const (
+	// BudgetActionRefuse 拒绝后续的 response.create
+	BudgetActionRefuse BudgetAction = iota
+	// BudgetActionClose 直接关闭会话
+	BudgetActionClose
+)
+
+// Budget 预算限制，值为 0 的字段表示不限制
+type Budget struct {
+	MaxTokens int64
+	MaxCost   float64
+	Action    BudgetAction
+}
+
+func (b *Budget) exceeded(tokens Tokens, cost float64) bool {
+	if b == nil {
+		return false
+	}
+	return (b.MaxTokens > 0 && tokens.Total() >= b.MaxTokens) || (b.MaxCost > 0 && cost >= b.MaxCost)
+}
+
+// BudgetScope 预算的统计范围
+type BudgetScope string
+
+const (
+	BudgetScopeSession BudgetScope = "session"
+	BudgetScopeClient  BudgetScope = "client"
+)
+
+// Exceeded 描述一次超出预算的情况
+type Exceeded struct {
+	Scope  BudgetScope
+	Action BudgetAction
+	Tokens Tokens
+	Cost   float64
+}
+
+func (e *Exceeded) Error() string {
+	return fmt.Sprintf("%s budget exceeded, tokens: %d, cost: %.6f", e.Scope, e.Tokens.Total(), e.Cost)
+}
+
+// Report 某个统计范围内的用量与费用
+type Report struct {
+	SessionID string  `json:"session_id,omitempty"`
+	Model     string  `json:"model,omitempty"`
+	Responses int     `json:"responses"`
+	Tokens    Tokens  `json:"tokens"`
+	Cost      float64 `json:"cost"`
+}
+
+type sessionUsage struct {
+	model     string
+	responses int
+	tokens    Tokens
+	cost      float64
+}
+
+// Tracker 汇总 response.done 中的用量，按会话和客户端两个维度统计并检查预算
+type Tracker struct {
+	mu            sync.Mutex
+	prices        PriceTable
+	sessionBudget *Budget
+	clientBudget  *Budget
+
+	sessions map[string]*sessionUsage
+	order    []string
+	current  string
+	total    sessionUsage
+	exceeded *Exceeded
+	notified map[BudgetScope]bool
+}
+
+func NewTracker(prices PriceTable) *Tracker {
+	return &Tracker{
+		prices:   prices,
+		sessions: make(map[string]*sessionUsage),
+		notified: make(map[BudgetScope]bool),
+	}
+}
+
+// SetSessionBudget 设置单个会话的预算，传 nil 取消限制
+func (t *Tracker) SetSessionBudget(b *Budget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessionBudget = b
+	t.checkLocked()
+}
+
+// SetClientBudget 设置整个客户端（跨会话）的预算，传 nil 取消限制
+func (t *Tracker) SetClientBudget(b *Budget) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clientBudget = b
+	t.checkLocked()
+}
+
+// Observe 处理一个服务端事件，返回新出现的超预算情况，没有则返回 nil
+func (t *Tracker) Observe(event *events.Event) *Exceeded {
+	if event == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch event.Type {
+	case events.RealtimeServerEventSessionCreated, events.RealtimeServerEventSessionUpdated:
+		if event.Session == nil {
+			return nil
+		}
+		s := t.sessionLocked(event.Session.ID)
+		if event.Session.Model != "" {
+			s.model = event.Session.Model
+		}
+		if event.Type == events.RealtimeServerEventSessionCreated {
+			// 新会话开始，会话级预算需要重新提醒
+			delete(t.notified, BudgetScopeSession)
+			t.checkLocked()
+		}
+	case events.RealtimeServerEventResponseDone:
+		if event.Response == nil || event.Response.Usage == nil {
+			return nil
+		}
+		s := t.sessionLocked(t.current)
+		tokens := FromUsage(event.Response.Usage)
+		cost := 0.0
+		if t.prices != nil {
+			cost = t.prices.Cost(s.model, tokens)
+		}
+		s.responses++
+		s.tokens.Add(tokens)
+		s.cost += cost
+		t.total.responses++
+		t.total.tokens.Add(tokens)
+		t.total.cost += cost
+		t.checkLocked()
+	default:
+		return nil
+	}
+	if t.exceeded == nil || t.notified[t.exceeded.Scope] {
+		return nil
+	}
+	t.notified[t.exceeded.Scope] = true
+	exceeded := *t.exceeded
+	return &exceeded
+}
+
+// Exceeded 返回当前是否超出预算，未超出时返回 nil
+func (t *Tracker) Exceeded() *Exceeded {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.exceeded == nil {
+		return nil
+	}
+	exceeded := *t.exceeded
+	return &exceeded
+}
+
+// Session 返回指定会话的用量，id 为空时返回当前会话
+func (t *Tracker) Session(id string) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id == "" {
+		id = t.current
+	}
+	s, ok := t.sessions[id]
+	if !ok {
+		return Report{SessionID: id}
+	}
+	return Report{SessionID: id, Model: s.model, Responses: s.responses, Tokens: s.tokens, Cost: s.cost}
+}
+
+// Sessions 按会话创建顺序返回所有会话的用量
+func (t *Tracker) Sessions() []Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	reports := make([]Report, 0, len(t.order))
+	for _, id := range t.order {
+		s := t.sessions[id]
+		reports = append(reports, Report{SessionID: id, Model: s.model, Responses: s.responses, Tokens: s.tokens, Cost: s.cost})
+	}
+	return reports
+}
+
+// Total 返回客户端维度的累计用量
+func (t *Tracker) Total() Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Report{Responses: t.total.responses, Tokens: t.total.tokens, Cost: t.total.cost}
+}
+
+func (t *Tracker) sessionLocked(id string) *sessionUsage {
+	s, ok := t.sessions[id]
+	if !ok {
+		s = &sessionUsage{}
+		t.sessions[id] = s
+		t.order = append(t.order, id)
+	}
+	t.current = id
+	return s
+}
+
+func (t *Tracker) checkLocked() {
+	t.exceeded = nil
+	if s, ok := t.sessions[t.current]; ok && t.sessionBudget.exceeded(s.tokens, s.cost) {
+		t.exceeded = &Exceeded{Scope: BudgetScopeSession, Action: t.sessionBudget.Action, Tokens: s.tokens, Cost: s.cost}
+	}
+	if t.clientBudget.exceeded(t.total.tokens, t.total.cost) {
+		// 客户端预算优先级更高，一旦超出后续会话也无法使用
+		t.exceeded = &Exceeded{Scope: BudgetScopeClient, Action: t.clientBudget.Action, Tokens: t.total.tokens, Cost: t.total.cost}
+	}
+}
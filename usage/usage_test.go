@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"testing"
+
+	"github.com/MetaGLM/glm-realtime-sdk/golang/events"
+)
+
+func responseDone(input, inputAudio, output, outputAudio int64) *events.Event {
+	return &events.Event{
+		Type: events.RealtimeServerEventResponseDone,
+		Response: &events.Response{
+			Usage: &events.Usage{
+				TotalTokens:        input + output,
+				InputTokens:        input,
+				OutputTokens:       output,
+				InputTokenDetails:  events.TokenDetails{AudioTokens: int(inputAudio)},
+				OutputTokenDetails: events.TokenDetails{AudioTokens: int(outputAudio)},
+			},
+		},
+	}
+}
+
+func TestTrackerAggregatesSessions(t *testing.T) {
+	tracker := NewTracker(StaticPriceTable{"": {InputText: 1, InputAudio: 2, OutputText: 3, OutputAudio: 4}})
+
+	tracker.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "s1"}})
+	tracker.Observe(responseDone(100, 60, 50, 40))
+	tracker.Observe(responseDone(100, 60, 50, 40))
+	tracker.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "s2"}})
+	tracker.Observe(responseDone(10, 0, 10, 0))
+
+	s1 := tracker.Session("s1")
+	if s1.Responses != 2 || s1.Tokens != (Tokens{InputText: 80, InputAudio: 120, OutputText: 20, OutputAudio: 80}) {
+		t.Fatalf("unexpected session usage: %+v", s1)
+	}
+	if want := (80*1 + 120*2 + 20*3 + 80*4) / 1e6; s1.Cost != want {
+		t.Fatalf("cost = %v, want %v", s1.Cost, want)
+	}
+	total := tracker.Total()
+	if total.Responses != 3 || total.Tokens.Total() != 320 {
+		t.Fatalf("unexpected total usage: %+v", total)
+	}
+	if current := tracker.Session(""); current.SessionID != "s2" {
+		t.Fatalf("current session = %q, want s2", current.SessionID)
+	}
+}
+
+func TestTrackerBudget(t *testing.T) {
+	tracker := NewTracker(nil)
+	tracker.SetSessionBudget(&Budget{MaxTokens: 100, Action: BudgetActionClose})
+	tracker.SetClientBudget(&Budget{MaxTokens: 150})
+
+	tracker.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "s1"}})
+	if exceeded := tracker.Observe(responseDone(40, 0, 40, 0)); exceeded != nil {
+		t.Fatalf("unexpected exceeded: %v", exceeded)
+	}
+	exceeded := tracker.Observe(responseDone(20, 0, 20, 0))
+	if exceeded == nil || exceeded.Scope != BudgetScopeSession || exceeded.Action != BudgetActionClose {
+		t.Fatalf("expected session budget exceeded, got %v", exceeded)
+	}
+	if again := tracker.Observe(responseDone(1, 0, 0, 0)); again != nil {
+		t.Fatalf("budget warning should only be reported once, got %v", again)
+	}
+
+	tracker.Observe(&events.Event{Type: events.RealtimeServerEventSessionCreated, Session: &events.Session{ID: "s2"}})
+	if tracker.Exceeded() != nil {
+		t.Fatalf("new session should start within budget")
+	}
+	exceeded = tracker.Observe(responseDone(40, 0, 30, 0))
+	if exceeded == nil || exceeded.Scope != BudgetScopeClient || exceeded.Action != BudgetActionRefuse {
+		t.Fatalf("expected client budget exceeded, got %v", exceeded)
+	}
+}